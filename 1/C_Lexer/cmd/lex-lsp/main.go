@@ -0,0 +1,17 @@
+// lex-lsp 是对这个仓库的C词法分析器的一层极简LSP封装：除了会话生命周期
+// 用的shutdown/exit之外，只认initialize、textDocument/didOpen、
+// textDocument/didChange、textDocument/semanticTokens/full这四个方法，目的是让编辑器不用
+// 自己重新实现一遍C的词法规则，就能拿到这个实现产出的语法高亮
+package main
+
+import (
+	"bufio"
+	"os"
+)
+
+func main() {
+	s := newServer()
+	in := bufio.NewReader(os.Stdin)
+	out := bufio.NewWriter(os.Stdout)
+	_ = s.run(in, out)
+}
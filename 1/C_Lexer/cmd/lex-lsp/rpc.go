@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// rpcMessage 是一条JSON-RPC 2.0消息的最小骨架：请求/响应/通知共用同一个
+// 结构，具体是哪一种由字段是否存在来区分，和LSP规范本身的做法一致
+type rpcMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// readMessage 按LSP的Content-Length头部framing从r里读出下一条消息的原始JSON
+func readMessage(r *bufio.Reader) ([]byte, error) {
+	length := -1
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break // 空行结束头部
+		}
+		if strings.HasPrefix(line, "Content-Length:") {
+			rest := strings.TrimPrefix(line, "Content-Length:")
+			n, err := strconv.Atoi(strings.TrimSpace(rest))
+			if err != nil {
+				return nil, fmt.Errorf("lex-lsp: 非法的Content-Length: %w", err)
+			}
+			length = n
+		}
+	}
+	if length < 0 {
+		return nil, fmt.Errorf("lex-lsp: 消息头部缺少Content-Length")
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// writeMessage 把一条消息编码成JSON，按同样的Content-Length framing写出去
+func writeMessage(w *bufio.Writer, msg rpcMessage) error {
+	msg.JSONRPC = "2.0"
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+	_, err = w.Write(body)
+	return err
+}
+
+// writeResponse 是writeMessage的常见特化：按请求的id回一个成功响应
+func writeResponse(w *bufio.Writer, id json.RawMessage, result interface{}) {
+	writeMessage(w, rpcMessage{ID: id, Result: result})
+}
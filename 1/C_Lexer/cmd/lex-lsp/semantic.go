@@ -0,0 +1,80 @@
+package main
+
+import "Compilation_principle/1/C_Lexer/lex"
+
+// semanticTokenTypes 是这个语言服务器在initialize响应里公布的语义标记
+// legend；下标就是encodeSemanticTokens写进data数组里的tokenType编号
+var semanticTokenTypes = []string{
+	"keyword", "identifier", "number", "string", "comment", "operator", "punctuation",
+}
+
+const (
+	semKeyword = iota
+	semIdent
+	semNumber
+	semString
+	semComment
+	semOperator
+	semPunctuation
+)
+
+// classify 把lang/c产生的具体TokenType归到semanticTokenTypes里的某一粗粒度
+// 语义类型上——编辑器的语法高亮只需要知道这几大类，不关心C词法的全部细节
+func classify(tokenType string) (int, bool) {
+	switch tokenType {
+	case "IDENT":
+		return semIdent, true
+	case "INT", "FLOAT":
+		return semNumber, true
+	case "STRING", "CHAR_LIT":
+		return semString, true
+	case "COMMENT_SINGLE", "COMMENT_MULTI":
+		return semComment, true
+	case "PLUS", "MINUS", "STAR", "SLASH", "EQUAL", "AND", "OR", "XOR", "NOT", "MOD",
+		"LSHIFT", "RSHIFT", "ANDAND", "OROR", "NOTNOT", "NEQ", "EQEQ", "LT", "GT", "LTEQ", "GTEQ",
+		"PLUSEQ", "MINUSEQ", "STAREQ", "SLASHEQ", "MODEQ", "ANDEQ", "OREQ", "XOREQ", "LSHIFTEQ", "RSHIFTEQ",
+		"INC", "DEC", "ARROW", "QUESTION":
+		return semOperator, true
+	case "SEMICOLON", "COMMA", "LPAREN", "RPAREN", "LBRACE", "RBRACE", "LBRACKET", "RBRACKET", "COLON", "ELLIPSIS", "DOT":
+		return semPunctuation, true
+	case "ILLEGAL", "EOF", "PREPROC":
+		return 0, false
+	default:
+		return semKeyword, true // 剩下的都是C的关键字（int/return/if/...）
+	}
+}
+
+// encodeSemanticTokens 用仓库自己的C词法分析器扫描text，按
+// textDocument/semanticTokens/full响应要求的相对增量编码产出data数组：
+// 每个token占5个int（deltaLine, deltaStartChar, length, tokenType,
+// tokenModifiers），行列相对于上一个输出的token。file是这份text对应的
+// 文档URI，只用来让产出的Token.File报告正确的来源，不影响编码本身
+func encodeSemanticTokens(file, text string) []int {
+	tokens, err := lex.LexFile("c", file, text)
+	if err != nil {
+		return []int{}
+	}
+
+	data := make([]int, 0, len(tokens)*5)
+	prevLine, prevCol := 0, 0
+	for _, tok := range tokens {
+		kind, ok := classify(string(tok.Type))
+		if !ok {
+			continue
+		}
+		if kind == semNumber && (tok.Value == "int" || tok.Value == "float") {
+			kind = semKeyword // int/float关键字和数字字面量共用INT/FLOAT这两个TokenType
+		}
+		line := tok.StartLine - 1 // LSP里行列都是0-based
+		col := tok.StartCol - 1
+
+		deltaLine := line - prevLine
+		deltaCol := col
+		if deltaLine == 0 {
+			deltaCol = col - prevCol
+		}
+		data = append(data, deltaLine, deltaCol, len([]rune(tok.Value)), kind, 0)
+		prevLine, prevCol = line, col
+	}
+	return data
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+)
+
+// server 保存每个已经didOpen/didChange过的文档的最新文本，key是LSP的
+// 文档URI；lex-lsp只把词法分析结果当只读产物用，不需要增量更新文本本身
+type server struct {
+	docs map[string]string
+}
+
+func newServer() *server {
+	return &server{docs: map[string]string{}}
+}
+
+// run 是主循环：从in按Content-Length framing读一条消息就分发一次，
+// 一直到输入流关闭（编辑器退出、管道断开）为止
+func (s *server) run(in *bufio.Reader, out *bufio.Writer) error {
+	for {
+		body, err := readMessage(in)
+		if err != nil {
+			return err
+		}
+		var req rpcMessage
+		if err := json.Unmarshal(body, &req); err != nil {
+			continue // 消息本身不是合法JSON-RPC，丢弃后继续等下一条
+		}
+		s.dispatch(req, out)
+	}
+}
+
+func (s *server) dispatch(req rpcMessage, out *bufio.Writer) {
+	switch req.Method {
+	case "initialize":
+		s.handleInitialize(req, out)
+	case "textDocument/didOpen":
+		s.handleDidOpen(req)
+	case "textDocument/didChange":
+		s.handleDidChange(req)
+	case "textDocument/semanticTokens/full":
+		s.handleSemanticTokens(req, out)
+	case "shutdown":
+		writeResponse(out, req.ID, nil)
+	case "exit":
+		os.Exit(0)
+	default:
+		// 通知/请求里我们不认识的方法：按LSP规范直接忽略，不回错误
+	}
+	out.Flush()
+}
+
+type textDocumentItem struct {
+	URI  string `json:"uri"`
+	Text string `json:"text"`
+}
+
+type didOpenParams struct {
+	TextDocument textDocumentItem `json:"textDocument"`
+}
+
+func (s *server) handleDidOpen(req rpcMessage) {
+	var params didOpenParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return
+	}
+	s.docs[params.TextDocument.URI] = params.TextDocument.Text
+}
+
+type textDocumentIdentifier struct {
+	URI string `json:"uri"`
+}
+
+type contentChange struct {
+	Text string `json:"text"`
+}
+
+type didChangeParams struct {
+	TextDocument   textDocumentIdentifier `json:"textDocument"`
+	ContentChanges []contentChange        `json:"contentChanges"`
+}
+
+// handleDidChange 只支持full-document同步（contentChanges末项的Text就是
+// 整份新文本），这是TextDocumentSyncKind.Full对应的做法，实现成本最低，
+// 对只读semanticTokens/full这个用途来说也完全够用
+func (s *server) handleDidChange(req rpcMessage) {
+	var params didChangeParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return
+	}
+	if len(params.ContentChanges) == 0 {
+		return
+	}
+	s.docs[params.TextDocument.URI] = params.ContentChanges[len(params.ContentChanges)-1].Text
+}
+
+type semanticTokensLegend struct {
+	TokenTypes     []string `json:"tokenTypes"`
+	TokenModifiers []string `json:"tokenModifiers"`
+}
+
+type semanticTokensOptions struct {
+	Legend semanticTokensLegend `json:"legend"`
+	Full   bool                 `json:"full"`
+}
+
+type serverCapabilities struct {
+	SemanticTokensProvider semanticTokensOptions `json:"semanticTokensProvider"`
+}
+
+type initializeResult struct {
+	Capabilities serverCapabilities `json:"capabilities"`
+}
+
+func (s *server) handleInitialize(req rpcMessage, out *bufio.Writer) {
+	result := initializeResult{
+		Capabilities: serverCapabilities{
+			SemanticTokensProvider: semanticTokensOptions{
+				Legend: semanticTokensLegend{TokenTypes: semanticTokenTypes, TokenModifiers: []string{}},
+				Full:   true,
+			},
+		},
+	}
+	writeResponse(out, req.ID, result)
+}
+
+type semanticTokensParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+}
+
+type semanticTokensResult struct {
+	Data []int `json:"data"`
+}
+
+func (s *server) handleSemanticTokens(req rpcMessage, out *bufio.Writer) {
+	var params semanticTokensParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		writeResponse(out, req.ID, semanticTokensResult{Data: []int{}})
+		return
+	}
+	text := s.docs[params.TextDocument.URI]
+	writeResponse(out, req.ID, semanticTokensResult{Data: encodeSemanticTokens(params.TextDocument.URI, text)})
+}
@@ -0,0 +1,102 @@
+// Package format 把lexcore.Token序列编码成给人看的文本，或者给其它工具
+// 消费的JSON/JSONL，不需要重新跑一遍词法分析就能集成进别的管线
+package format
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"Compilation_principle/1/C_Lexer/lexcore"
+)
+
+// Trivia 是挂在某个有意义token之前的注释：仿照Roslyn/rust-analyzer的做法，
+// 词法分析本身仍然只产生"有意义"的token，这里把中间跳过的注释重新挂到
+// 紧随其后的token上，下游工具（比如格式化器）就不会丢失它们
+type Trivia struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// Position 是JSON输出里的行列位置，字段取自对应Token的Start*/End*
+type Position struct {
+	Line int `json:"line"`
+	Col  int `json:"col"`
+}
+
+// JSONToken 是一个token在json/jsonl输出模式下的外部表现形式
+type JSONToken struct {
+	Type   string   `json:"type"`
+	Value  string   `json:"value"`
+	File   string   `json:"file"`
+	Start  Position `json:"start"`
+	End    Position `json:"end"`
+	Trivia []Trivia `json:"trivia,omitempty"`
+}
+
+func isComment(t lexcore.TokenType) bool {
+	return t == "COMMENT_SINGLE" || t == "COMMENT_MULTI"
+}
+
+// AttachTrivia 把tokens里的注释token折叠进紧跟其后的第一个非注释token的
+// Trivia字段，返回的切片里不再单独出现注释token
+func AttachTrivia(tokens []lexcore.Token) []JSONToken {
+	out := make([]JSONToken, 0, len(tokens))
+	var pending []Trivia
+	var lastFile string
+	var lastEnd Position
+	for _, tok := range tokens {
+		if isComment(tok.Type) {
+			pending = append(pending, Trivia{Type: string(tok.Type), Value: tok.Value})
+			lastFile = tok.File
+			lastEnd = Position{Line: tok.EndLine, Col: tok.EndCol}
+			continue
+		}
+		out = append(out, JSONToken{
+			Type:   string(tok.Type),
+			Value:  tok.Value,
+			File:   tok.File,
+			Start:  Position{Line: tok.StartLine, Col: tok.StartCol},
+			End:    Position{Line: tok.EndLine, Col: tok.EndCol},
+			Trivia: pending,
+		})
+		pending = nil
+	}
+	if len(pending) > 0 {
+		// 输入末尾的注释后面没有紧跟任何有意义的token（调用方通常已经把
+		// EOF本身砍掉了），这里补一个没有Value的合成EOF条目把它们挂上去，
+		// 否则这些注释会在输出里被悄悄丢掉
+		out = append(out, JSONToken{
+			Type:   "EOF",
+			File:   lastFile,
+			Start:  lastEnd,
+			End:    lastEnd,
+			Trivia: pending,
+		})
+	}
+	return out
+}
+
+// WriteJSON 把整份token序列编码成一个JSON数组写出去
+func WriteJSON(w io.Writer, tokens []lexcore.Token) error {
+	return json.NewEncoder(w).Encode(AttachTrivia(tokens))
+}
+
+// WriteJSONL 逐个token编码成一行JSON对象写出去（JSON Lines），
+// 比WriteJSON更适合流式处理——下游不用等到整份输出结束才能开始解析
+func WriteJSONL(w io.Writer, tokens []lexcore.Token) error {
+	enc := json.NewEncoder(w)
+	for _, jt := range AttachTrivia(tokens) {
+		if err := enc.Encode(jt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteText 是原本的纯文本输出模式：每行打印一个token的Go结构体
+func WriteText(w io.Writer, tokens []lexcore.Token) {
+	for _, tok := range tokens {
+		fmt.Fprintln(w, tok)
+	}
+}
@@ -0,0 +1,685 @@
+package c
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// 本文件实现一个小型的、类 lex/flex 的规则引擎：使用者用正则表达式登记
+// (pattern, TokenType) 规则（RuleSet），引擎对每条规则用Thompson构造法
+// 生成NFA片段，再用子集构造把所有规则的NFA一次性转成一个DFA，最后用
+// Hopcroft分割精化算法做状态最小化。扫描时贪婪地在DFA上前进，按照
+// "最长匹配"规则决定token边界，等长时以规则登记的先后顺序（优先级）
+// 打破平局——这正是Lexer.NextToken现在用来代替手写if级联的机制。
+
+// ---------- 正则语法树与解析 ----------
+
+type reNode interface{}
+
+type reLit struct{ ch byte }
+type reAny struct{}
+type reClass struct {
+	neg    bool
+	ranges [][2]byte // 闭区间 [lo, hi] 的集合
+}
+type reConcat struct{ parts []reNode }
+type reAlt struct{ parts []reNode }
+type reStar struct{ sub reNode }
+type rePlus struct{ sub reNode }
+type reOpt struct{ sub reNode }
+
+type reParser struct {
+	pat string
+	pos int
+}
+
+// parseRegex 支持：字面字符、转义（\n \t \r \d \w \s 以及对元字符的转义）、
+// 任意字符 .、字符类 [a-z^...]、分组 ( )、选择 |、重复 * + ?
+func parseRegex(pat string) (reNode, error) {
+	p := &reParser{pat: pat}
+	node, err := p.parseAlt()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.pat) {
+		return nil, fmt.Errorf("正则 %q 在第 %d 个字符处存在多余内容", pat, p.pos)
+	}
+	return node, nil
+}
+
+func (p *reParser) peek() byte {
+	if p.pos >= len(p.pat) {
+		return 0
+	}
+	return p.pat[p.pos]
+}
+
+func (p *reParser) parseAlt() (reNode, error) {
+	first, err := p.parseConcat()
+	if err != nil {
+		return nil, err
+	}
+	alts := []reNode{first}
+	for p.peek() == '|' {
+		p.pos++
+		n, err := p.parseConcat()
+		if err != nil {
+			return nil, err
+		}
+		alts = append(alts, n)
+	}
+	if len(alts) == 1 {
+		return alts[0], nil
+	}
+	return &reAlt{parts: alts}, nil
+}
+
+func (p *reParser) parseConcat() (reNode, error) {
+	var parts []reNode
+	for p.pos < len(p.pat) && p.peek() != '|' && p.peek() != ')' {
+		n, err := p.parseRepeat()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, n)
+	}
+	if len(parts) == 1 {
+		return parts[0], nil
+	}
+	return &reConcat{parts: parts}, nil
+}
+
+func (p *reParser) parseRepeat() (reNode, error) {
+	n, err := p.parseAtom()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch p.peek() {
+		case '*':
+			p.pos++
+			n = &reStar{sub: n}
+		case '+':
+			p.pos++
+			n = &rePlus{sub: n}
+		case '?':
+			p.pos++
+			n = &reOpt{sub: n}
+		default:
+			return n, nil
+		}
+	}
+}
+
+func (p *reParser) parseAtom() (reNode, error) {
+	switch p.peek() {
+	case '(':
+		p.pos++
+		n, err := p.parseAlt()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, fmt.Errorf("正则 %q 缺少闭合的 ')'", p.pat)
+		}
+		p.pos++
+		return n, nil
+	case '.':
+		p.pos++
+		return &reAny{}, nil
+	case '[':
+		return p.parseClass()
+	case '\\':
+		p.pos++
+		return p.parseEscape()
+	case 0:
+		return nil, fmt.Errorf("正则 %q 意外结束", p.pat)
+	default:
+		ch := p.peek()
+		p.pos++
+		return &reLit{ch: ch}, nil
+	}
+}
+
+// escapeClassRanges 识别展开成一组范围的转义：\d \w \s，在字符类内外含义一致
+func escapeClassRanges(ch byte) ([][2]byte, bool) {
+	switch ch {
+	case 'd':
+		return [][2]byte{{'0', '9'}}, true
+	case 'w':
+		return [][2]byte{{'a', 'z'}, {'A', 'Z'}, {'0', '9'}, {'_', '_'}}, true
+	case 's':
+		return [][2]byte{{' ', ' '}, {'\t', '\t'}, {'\n', '\n'}, {'\r', '\r'}}, true
+	}
+	return nil, false
+}
+
+// escapeByte 识别展开成单个字节的转义：\n \t \r；其它字符原样返回，
+// 这样 \. \\ \[ 这类"转义掉元字符本身"的写法也能直接复用
+func escapeByte(ch byte) byte {
+	switch ch {
+	case 'n':
+		return '\n'
+	case 't':
+		return '\t'
+	case 'r':
+		return '\r'
+	default:
+		return ch
+	}
+}
+
+func (p *reParser) parseEscape() (reNode, error) {
+	ch := p.peek()
+	p.pos++
+	if ch == 0 {
+		return nil, fmt.Errorf("正则 %q 在转义处意外结束", p.pat)
+	}
+	if ranges, ok := escapeClassRanges(ch); ok {
+		return &reClass{ranges: ranges}, nil
+	}
+	return &reLit{ch: escapeByte(ch)}, nil
+}
+
+// readClassAtom 读取字符类 [...] 里的一个原子：普通字符或转义都解析成单个
+// 字节（isSet=false），\d \w \s 这类展开成一组range（isSet=true）；和 parseEscape
+// 共用 escapeByte/escapeClassRanges，确保转义在字符类内外含义一致
+func (p *reParser) readClassAtom() (b byte, ranges [][2]byte, isSet bool) {
+	ch := p.peek()
+	if ch != '\\' {
+		p.pos++
+		return ch, nil, false
+	}
+	p.pos++
+	esc := p.peek()
+	if rs, ok := escapeClassRanges(esc); ok {
+		p.pos++
+		return 0, rs, true
+	}
+	p.pos++
+	return escapeByte(esc), nil, false
+}
+
+func (p *reParser) parseClass() (reNode, error) {
+	p.pos++ // 跳过 '['
+	c := &reClass{}
+	if p.peek() == '^' {
+		c.neg = true
+		p.pos++
+	}
+	first := true
+	for p.pos < len(p.pat) && (p.peek() != ']' || first) {
+		first = false
+		lo, ranges, isSet := p.readClassAtom()
+		if isSet {
+			c.ranges = append(c.ranges, ranges...)
+			continue
+		}
+		hi := lo
+		if p.peek() == '-' && p.pos+1 < len(p.pat) && p.pat[p.pos+1] != ']' {
+			p.pos++
+			if hiByte, _, hiIsSet := p.readClassAtom(); !hiIsSet {
+				hi = hiByte
+			}
+		}
+		c.ranges = append(c.ranges, [2]byte{lo, hi})
+	}
+	if p.peek() != ']' {
+		return nil, fmt.Errorf("正则 %q 缺少闭合的 ']'", p.pat)
+	}
+	p.pos++
+	return c, nil
+}
+
+// reEscapeLiteral 把一个普通字符串里的正则元字符转义掉，用来把
+// 关键字、固定的运算符拼写这类"字面量"直接当成规则模式使用
+func reEscapeLiteral(s string) string {
+	var b strings.Builder
+	const special = `\.*+?()[]|^$`
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if strings.IndexByte(special, ch) >= 0 {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(ch)
+	}
+	return b.String()
+}
+
+// ---------- Thompson构造：正则语法树 -> NFA片段 ----------
+
+type nfaState struct {
+	trans map[byte][]int
+	eps   []int
+}
+
+type nfaBuilder struct {
+	states []nfaState
+}
+
+func (b *nfaBuilder) newState() int {
+	b.states = append(b.states, nfaState{trans: map[byte][]int{}})
+	return len(b.states) - 1
+}
+
+func (b *nfaBuilder) addEps(from, to int) {
+	b.states[from].eps = append(b.states[from].eps, to)
+}
+
+func (b *nfaBuilder) addTrans(from int, ch byte, to int) {
+	b.states[from].trans[ch] = append(b.states[from].trans[ch], to)
+}
+
+// frag 是一个NFA片段：start是入口状态，out是唯一的出口状态（尚无转移），
+// 拼接/选择/重复时通过给out加epsilon边来把片段粘在一起
+type frag struct{ start, out int }
+
+func (b *nfaBuilder) build(n reNode) frag {
+	switch v := n.(type) {
+	case *reLit:
+		s, o := b.newState(), b.newState()
+		b.addTrans(s, v.ch, o)
+		return frag{s, o}
+	case *reAny:
+		s, o := b.newState(), b.newState()
+		for c := 1; c < 256; c++ { // 排除哨兵字节 0（文件结束）
+			b.addTrans(s, byte(c), o)
+		}
+		return frag{s, o}
+	case *reClass:
+		s, o := b.newState(), b.newState()
+		for c := 1; c < 256; c++ {
+			ok := classContains(v, byte(c))
+			if ok {
+				b.addTrans(s, byte(c), o)
+			}
+		}
+		return frag{s, o}
+	case *reConcat:
+		if len(v.parts) == 0 {
+			s := b.newState()
+			return frag{s, s}
+		}
+		f := b.build(v.parts[0])
+		for _, part := range v.parts[1:] {
+			g := b.build(part)
+			b.addEps(f.out, g.start)
+			f = frag{f.start, g.out}
+		}
+		return f
+	case *reAlt:
+		s, o := b.newState(), b.newState()
+		for _, part := range v.parts {
+			f := b.build(part)
+			b.addEps(s, f.start)
+			b.addEps(f.out, o)
+		}
+		return frag{s, o}
+	case *reStar:
+		s, o := b.newState(), b.newState()
+		f := b.build(v.sub)
+		b.addEps(s, f.start)
+		b.addEps(s, o)
+		b.addEps(f.out, f.start)
+		b.addEps(f.out, o)
+		return frag{s, o}
+	case *rePlus:
+		f := b.build(v.sub)
+		o := b.newState()
+		b.addEps(f.out, f.start)
+		b.addEps(f.out, o)
+		return frag{f.start, o}
+	case *reOpt:
+		s, o := b.newState(), b.newState()
+		f := b.build(v.sub)
+		b.addEps(s, f.start)
+		b.addEps(s, o)
+		b.addEps(f.out, o)
+		return frag{s, o}
+	}
+	panic(fmt.Sprintf("dfa: 未知的正则节点 %T", n))
+}
+
+func classContains(c *reClass, ch byte) bool {
+	in := false
+	for _, r := range c.ranges {
+		if ch >= r[0] && ch <= r[1] {
+			in = true
+			break
+		}
+	}
+	if c.neg {
+		return !in
+	}
+	return in
+}
+
+// ---------- 规则表 ----------
+
+// Rule 描述一条词法规则：命中 Pattern 时产生 Type。等长匹配时，
+// 先登记的规则（Priority 更小）胜出——这让关键字可以写成比标识符
+// 规则更高优先级的字面量规则，复现 lex/flex "保留字先于标识符" 的习惯。
+type Rule struct {
+	Pattern  string
+	Type     TokenType
+	Priority int
+}
+
+// RuleSet 是使用者登记 (regex, TokenType) 规则的地方，登记完之后交给
+// BuildDFA/NewEngine 编译；规则命中时只产出对应的TokenType，不执行其它动作
+type RuleSet struct {
+	rules []Rule
+}
+
+func NewRuleSet() *RuleSet { return &RuleSet{} }
+
+// Add 登记一条规则，pattern 是本文件支持的正则语法，typ 是命中后的TokenType
+func (rs *RuleSet) Add(pattern string, typ TokenType) {
+	rs.rules = append(rs.rules, Rule{Pattern: pattern, Type: typ, Priority: len(rs.rules)})
+}
+
+// ---------- 子集构造：NFA -> DFA ----------
+
+type DFAState struct {
+	trans   [256]int // -1 表示没有对应字符的转移
+	accept  bool
+	ruleIdx int // accept 为 true 时，命中的规则下标；否则为 -1
+}
+
+type DFA struct {
+	start  int
+	states []DFAState
+	rules  []Rule
+}
+
+func epsClosure(b *nfaBuilder, set map[int]bool) {
+	stack := make([]int, 0, len(set))
+	for s := range set {
+		stack = append(stack, s)
+	}
+	for len(stack) > 0 {
+		s := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, t := range b.states[s].eps {
+			if !set[t] {
+				set[t] = true
+				stack = append(stack, t)
+			}
+		}
+	}
+}
+
+func setKey(set map[int]bool) string {
+	ids := make([]int, 0, len(set))
+	for s := range set {
+		ids = append(ids, s)
+	}
+	sort.Ints(ids)
+	return fmt.Sprint(ids)
+}
+
+// BuildDFA 把一组规则的正则表达式用Thompson构造法各自生成NFA片段，
+// 在一个共同的起始状态下用epsilon边连接起来，再用子集构造转成DFA
+func BuildDFA(rs *RuleSet) (*DFA, error) {
+	b := &nfaBuilder{}
+	start := b.newState()
+	acceptOf := make(map[int]int) // NFA状态 -> 命中的规则下标（同一出口状态取下标最小者）
+	for idx, r := range rs.rules {
+		node, err := parseRegex(r.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("规则 %q: %w", r.Pattern, err)
+		}
+		f := b.build(node)
+		b.addEps(start, f.start)
+		if prev, ok := acceptOf[f.out]; !ok || idx < prev {
+			acceptOf[f.out] = idx
+		}
+	}
+
+	dfa := &DFA{rules: rs.rules}
+	setIndex := map[string]int{}
+	var queue []map[int]bool
+
+	addSet := func(set map[int]bool) int {
+		k := setKey(set)
+		if i, ok := setIndex[k]; ok {
+			return i
+		}
+		st := DFAState{ruleIdx: -1}
+		for c := range st.trans {
+			st.trans[c] = -1
+		}
+		best := -1
+		for nfaSt := range set {
+			if rIdx, ok := acceptOf[nfaSt]; ok && (best == -1 || rIdx < best) {
+				best = rIdx
+			}
+		}
+		if best != -1 {
+			st.accept = true
+			st.ruleIdx = best
+		}
+		i := len(dfa.states)
+		dfa.states = append(dfa.states, st)
+		setIndex[k] = i
+		queue = append(queue, set)
+		return i
+	}
+
+	startSet := map[int]bool{start: true}
+	epsClosure(b, startSet)
+	dfa.start = addSet(startSet)
+
+	for qi := 0; qi < len(queue); qi++ {
+		set := queue[qi]
+		for c := 1; c < 256; c++ {
+			moved := map[int]bool{}
+			for s := range set {
+				for _, t := range b.states[s].trans[byte(c)] {
+					moved[t] = true
+				}
+			}
+			if len(moved) == 0 {
+				continue
+			}
+			epsClosure(b, moved)
+			dfa.states[qi].trans[c] = addSet(moved)
+		}
+	}
+	return dfa, nil
+}
+
+// ---------- Hopcroft分割精化：DFA最小化 ----------
+
+// Minimize 合并等价状态：两个状态只有在"接受状态归属同一条规则（或都不接受）"
+// 且"对每个输入字节的后继都落在同一分区"时才会被认为等价
+func (d *DFA) Minimize() *DFA {
+	n := len(d.states)
+	group := make([]int, n)
+	initGroups := map[string]int{}
+	for s := 0; s < n; s++ {
+		var key string
+		if d.states[s].accept {
+			key = fmt.Sprintf("A%d", d.states[s].ruleIdx)
+		} else {
+			key = "N"
+		}
+		id, ok := initGroups[key]
+		if !ok {
+			id = len(initGroups)
+			initGroups[key] = id
+		}
+		group[s] = id
+	}
+
+	for {
+		sig := make([]string, n)
+		newGroups := map[string]int{}
+		for s := 0; s < n; s++ {
+			var b strings.Builder
+			fmt.Fprintf(&b, "%d|", group[s])
+			for c := 0; c < 256; c++ {
+				t := d.states[s].trans[c]
+				if t == -1 {
+					b.WriteString("-,")
+				} else {
+					fmt.Fprintf(&b, "%d,", group[t])
+				}
+			}
+			sig[s] = b.String()
+		}
+		changed := false
+		final := make([]int, n)
+		for s := 0; s < n; s++ {
+			id, ok := newGroups[sig[s]]
+			if !ok {
+				id = len(newGroups)
+				newGroups[sig[s]] = id
+			}
+			final[s] = id
+			if id != group[s] {
+				changed = true
+			}
+		}
+		group = final
+		if !changed {
+			break
+		}
+	}
+
+	numGroups := 0
+	for _, g := range group {
+		if g+1 > numGroups {
+			numGroups = g + 1
+		}
+	}
+	min := &DFA{rules: d.rules, states: make([]DFAState, numGroups), start: group[d.start]}
+	seen := make([]bool, numGroups)
+	for s := 0; s < n; s++ {
+		g := group[s]
+		if seen[g] {
+			continue
+		}
+		seen[g] = true
+		min.states[g].accept = d.states[s].accept
+		min.states[g].ruleIdx = d.states[s].ruleIdx
+		for c := 0; c < 256; c++ {
+			t := d.states[s].trans[c]
+			if t == -1 {
+				min.states[g].trans[c] = -1
+			} else {
+				min.states[g].trans[c] = group[t]
+			}
+		}
+	}
+	return min
+}
+
+// ---------- 扫描：贪婪最长匹配 + 回退 ----------
+
+// Engine 包装一个已经最小化的DFA，提供从任意位置出发做最长匹配的能力
+type Engine struct {
+	dfa *DFA
+}
+
+// NewEngine 编译一组规则：Thompson构造 -> 子集构造 -> Hopcroft最小化
+func NewEngine(rs *RuleSet) (*Engine, error) {
+	dfa, err := BuildDFA(rs)
+	if err != nil {
+		return nil, err
+	}
+	return &Engine{dfa: dfa.Minimize()}, nil
+}
+
+// ByteSource 是Engine扫描时需要的最小随机访问能力：给定一个绝对偏移，
+// 返回该处的字节。流式的Lexer用双缓冲实现它，不需要把整个输入放进内存；
+// 普通字符串输入同样可以直接实现这个接口。
+type ByteSource interface {
+	ByteAt(pos int) (b byte, ok bool)
+}
+
+// Match 从 src 的 pos 位置开始贪婪地在DFA上前进。沿途记住最近一次经过的
+// 接受状态（以及它对应的匹配长度），一旦转移走进死胡同（没有后续转移，
+// 或者 src 已经没有更多字节）就回退到那个最长的合法匹配；
+// length==0 表示没有任何规则匹配。
+func (e *Engine) Match(src ByteSource, pos int) (length int, typ TokenType, ok bool) {
+	state := e.dfa.start
+	lastLen, lastRule := 0, -1
+	i := pos
+	for {
+		ch, has := src.ByteAt(i)
+		if !has {
+			break
+		}
+		next := e.dfa.states[state].trans[ch]
+		if next == -1 {
+			break
+		}
+		state = next
+		i++
+		if e.dfa.states[state].accept {
+			lastLen, lastRule = i-pos, e.dfa.states[state].ruleIdx
+		}
+	}
+	if lastRule == -1 {
+		return 0, "", false
+	}
+	return lastLen, e.dfa.rules[lastRule].Type, true
+}
+
+// buildCTokenRuleSet 把现有的C语言Token集合重新表达为规则表：关键字作为
+// 字面量规则登记在前（等长匹配时优先于标识符规则命中），标识符、数字、
+// 字符串/字符、注释以及'#'兜底规则各自对应正则，由longest-match决定切分；
+// 标点和运算符不在这张表里，见 operators.go。
+func buildCTokenRuleSet() *RuleSet {
+	rs := NewRuleSet()
+	kw := make([]string, 0, len(keywords))
+	for word := range keywords {
+		kw = append(kw, word)
+	}
+	sort.Strings(kw) // 固定顺序，保证每次构建出的DFA一致
+	for _, word := range kw {
+		rs.Add(reEscapeLiteral(word), keywords[word])
+	}
+
+	rs.Add(`[A-Za-z_][A-Za-z0-9_]*`, IDENT)
+
+	// 整数：十六进制/二进制/八进制/十进制，外加u/l/ll一类的后缀（顺序任意、大小写不敏感）
+	rs.Add(`0[xX][0-9a-fA-F]+[uUlL]*`, INT)
+	rs.Add(`0[bB][01]+[uUlL]*`, INT)
+	rs.Add(`0[0-7]*[uUlL]*`, INT)
+	rs.Add(`[1-9][0-9]*[uUlL]*`, INT)
+
+	// 浮点数：十六进制浮点数必须带 p/P 指数；十进制的指数、小数点都可以省略其一，
+	// 但至少要出现小数点或指数之一才会被识别成FLOAT而不是上面的INT
+	rs.Add(`0[xX][0-9a-fA-F]*\.[0-9a-fA-F]*[pP][+-]?[0-9]+[fFlL]?`, FLOAT)
+	rs.Add(`0[xX][0-9a-fA-F]+[pP][+-]?[0-9]+[fFlL]?`, FLOAT)
+	rs.Add(`[0-9]+\.[0-9]*([eE][+-]?[0-9]+)?[fFlL]?`, FLOAT)
+	rs.Add(`\.[0-9]+([eE][+-]?[0-9]+)?[fFlL]?`, FLOAT)
+	rs.Add(`[0-9]+[eE][+-]?[0-9]+[fFlL]?`, FLOAT)
+
+	// 字符串/字符字面量：可选的宽字符前缀 u8/L/u/U，内容允许任意转义序列
+	rs.Add(`(u8|L|u|U)?"([^"\\]|\\.)*"`, STRING)
+	rs.Add(`(L|u|U)?'([^'\\]|\\.)*'`, CHAR_LIT)
+
+	rs.Add(`//[^\n]*`, COMMENT_SINGLE)
+	rs.Add(`/\*([^*]|\*+[^*/])*\*+/`, COMMENT_MULTI)
+
+	// 标点和运算符不在这里：它们的拼写都落在1~3个字节以内，交给
+	// operators.go 里的运算符trie做最长匹配，规则不需要写成正则。
+	// 这里只留下这条变长的兜底规则，识别预处理阶段没能消化掉的'#'开头内容。
+	rs.Add(`#[^ \t\r\n]*`, PREPROC)
+	return rs
+}
+
+// cTokenEngine 是进程级别只构建一次的C token DFA，NextToken 用它做识别
+var cTokenEngine = mustBuildEngine(buildCTokenRuleSet())
+
+func mustBuildEngine(rs *RuleSet) *Engine {
+	e, err := NewEngine(rs)
+	if err != nil {
+		panic(fmt.Sprintf("dfa: 构建C token引擎失败: %v", err))
+	}
+	return e
+}
@@ -0,0 +1,119 @@
+package c
+
+import "testing"
+
+// stringSource 是测试里用到的最小 ByteSource 实现，直接包一个字符串
+type stringSource string
+
+func (s stringSource) ByteAt(pos int) (byte, bool) {
+	if pos < 0 || pos >= len(s) {
+		return 0, false
+	}
+	return s[pos], true
+}
+
+// 针对 dfa.go 里的正则 -> NFA -> DFA -> 最小化流水线做表驱动测试：
+// 每条规则各自命中一个模式，断言 Engine.Match 在指定输入上给出的
+// 最长匹配长度和对应的规则类型
+func TestEngineMatchBasics(t *testing.T) {
+	rs := NewRuleSet()
+	rs.Add(`[A-Za-z_][A-Za-z0-9_]*`, IDENT)
+	rs.Add(`[0-9]+`, INT)
+	rs.Add(`[0-9]+\.[0-9]*`, FLOAT)
+	rs.Add(`"([^"\\]|\\.)*"`, STRING)
+	engine, err := NewEngine(rs)
+	if err != nil {
+		t.Fatalf("NewEngine 返回了意外的错误: %v", err)
+	}
+
+	cases := []struct {
+		input     string
+		wantLen   int
+		wantType  TokenType
+		wantMatch bool
+	}{
+		{"foo bar", 3, IDENT, true},
+		{"foo123 x", 6, IDENT, true},
+		{"123abc", 3, INT, true}, // 数字开头不满足IDENT规则，只有INT能匹配前缀
+		{"42;", 2, INT, true},
+		{"3.14;", 4, FLOAT, true},
+		{`"ab\"c" rest`, 7, STRING, true},
+		{"   ", 0, "", false}, // 空白不在任何规则里，长度为0且不匹配
+	}
+	for _, c := range cases {
+		length, typ, ok := engine.Match(stringSource(c.input), 0)
+		if ok != c.wantMatch {
+			t.Errorf("Match(%q) ok = %v, want %v", c.input, ok, c.wantMatch)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if length != c.wantLen || typ != c.wantType {
+			t.Errorf("Match(%q) = (%d, %s), want (%d, %s)", c.input, length, typ, c.wantLen, c.wantType)
+		}
+	}
+}
+
+// 数字紧跟字母时，IDENT规则不能以数字开头，DFA在'a'处走进死胡同，
+// 回退到目前为止最长的合法匹配——INT规则的前3个数字，而不是整个"123abc"
+func TestEngineMatchLongestWins(t *testing.T) {
+	rs := NewRuleSet()
+	rs.Add(`[A-Za-z_][A-Za-z0-9_]*`, IDENT)
+	rs.Add(`[0-9]+`, INT)
+	engine, err := NewEngine(rs)
+	if err != nil {
+		t.Fatalf("NewEngine 返回了意外的错误: %v", err)
+	}
+	length, typ, ok := engine.Match(stringSource("123abc"), 0)
+	if !ok || typ != INT || length != 3 {
+		t.Fatalf("Match(%q) = (%d, %s, %v), want (3, %s, true)", "123abc", length, typ, ok, INT)
+	}
+}
+
+// 等长匹配时，先登记的规则（关键字）应当赢过后登记的规则（标识符），
+// 复现 lex/flex "保留字先于标识符" 的优先级约定
+func TestEngineMatchPriorityOnTie(t *testing.T) {
+	rs := NewRuleSet()
+	rs.Add(`if`, IF)
+	rs.Add(`[A-Za-z_][A-Za-z0-9_]*`, IDENT)
+	engine, err := NewEngine(rs)
+	if err != nil {
+		t.Fatalf("NewEngine 返回了意外的错误: %v", err)
+	}
+	length, typ, ok := engine.Match(stringSource("if (x)"), 0)
+	if !ok || typ != IF || length != 2 {
+		t.Fatalf("Match(%q) = (%d, %s, %v), want (2, %s, true)", "if (x)", length, typ, ok, IF)
+	}
+	length, typ, ok = engine.Match(stringSource("ifx (x)"), 0)
+	if !ok || typ != IDENT || length != 3 {
+		t.Fatalf("Match(%q) = (%d, %s, %v), want (3, %s, true)", "ifx (x)", length, typ, ok, IDENT)
+	}
+}
+
+// buildCTokenRuleSet/cTokenEngine 是词法分析器实际使用的规则表，这里用
+// 几个典型片段验证最小化后的引擎仍然给出正确的切分
+func TestCTokenEngineMatch(t *testing.T) {
+	cases := []struct {
+		input    string
+		wantLen  int
+		wantType TokenType
+	}{
+		{"return 0;", 6, RETURN},
+		{"x1", 2, IDENT},
+		{"0x1A", 4, INT},
+		{"3.14", 4, FLOAT},
+		{`"hi"`, 4, STRING},
+		{"// c", 4, COMMENT_SINGLE},
+	}
+	for _, c := range cases {
+		length, typ, ok := cTokenEngine.Match(stringSource(c.input), 0)
+		if !ok {
+			t.Errorf("cTokenEngine.Match(%q) 没有命中任何规则", c.input)
+			continue
+		}
+		if length != c.wantLen || typ != c.wantType {
+			t.Errorf("cTokenEngine.Match(%q) = (%d, %s), want (%d, %s)", c.input, length, typ, c.wantLen, c.wantType)
+		}
+	}
+}
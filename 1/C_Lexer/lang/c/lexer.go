@@ -0,0 +1,405 @@
+// Package c 是C语言的词法分析前端：预处理器、DFA扫描引擎和C11字面量
+// 语法都在这里，对外通过 lexcore.Scanner 接口暴露，好让 lex.Lex 按需要
+// 和Go/JS等其它语言前端一视同仁地选用
+package c
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"Compilation_principle/1/C_Lexer/lexcore"
+)
+
+// Token 和 TokenType 直接复用 lexcore 里定义的共享结构，这样本包写出来的
+// Token才能通过 lexcore.Scanner 接口被其它语言前端的调用者统一处理
+type Token = lexcore.Token
+type TokenType = lexcore.TokenType
+
+// 定义了一系列常量，表示不同的标记类型，方便在词法分析过程中进行分类和判断
+const (
+	ILLEGAL        = "ILLEGAL"        // 非法标记，用于表示在词法分析中遇到不符合语法规则的情况
+	EOF            = "EOF"            // 文件结束，当读取到输入源代码的末尾时，返回此标记类型
+	IDENT          = "IDENT"          // 标识符，用于表示变量名、函数名等自定义的名称
+	INT            = "INT"            // 整数，用于表示整数类型的数值
+	FLOAT          = "FLOAT"          // 浮点数，用于表示带有小数部分的数值
+	STRING         = "STRING"         // 字符串字面量，用于表示用双引号括起来的字符序列
+	RETURN         = "RETURN"         // 返回标志，用于表示C语言中的return关键字
+	PLUS           = "PLUS"           // 加号运算符
+	MINUS          = "MINUS"          // 减号运算符
+	STAR           = "STAR"           // 乘号运算符
+	SLASH          = "SLASH"          // 除号运算符
+	EQUAL          = "EQUAL"          // 等号运算符
+	SEMICOLON      = "SEMICOLON"      // 分号
+	COMMA          = "COMMA"          // 逗号
+	LPAREN         = "LPAREN"         // 左括号
+	RPAREN         = "RPAREN"         // 右括号
+	LBRACE         = "LBRACE"         // 左大括号
+	RBRACE         = "RBRACE"         // 右大括号
+	PREPROC        = "PREPROC"        // 预处理指令标记
+	VOID           = "VOID"           // void关键字标记
+	SIGNED         = "SIGNED"         // signed关键字标记
+	UNSIGNED       = "UNSIGNED"       // unsigned关键字标记
+	SHORT          = "SHORT"          // short关键字标记
+	LONG           = "LONG"           // long关键字标记
+	DOUBLE         = "DOUBLE"         // double关键字标记
+	CHAR           = "CHAR"           // char关键字标记
+	ENUM           = "ENUM"           // enum关键字标记
+	STRUCT         = "STRUCT"         // struct关键字标记
+	UNION          = "UNION"          // union关键字标记
+	TYPEDEF        = "TYPEDEF"        // typedef关键字标记
+	CONST          = "CONST"          // const关键字标记
+	VOLATILE       = "VOLATILE"       // volatile关键字标记
+	AUTO           = "AUTO"           // auto关键字标记
+	STATIC         = "STATIC"         // static关键字标记
+	EXTERN         = "EXTERN"         // extern关键字标记
+	REGISTER       = "REGISTER"       // register关键字标记
+	SIZEOF         = "SIZEOF"         // sizeof关键字标记
+	GOTO           = "GOTO"           // goto关键字标记
+	BREAK          = "BREAK"          // break关键字标记
+	CONTINUE       = "CONTINUE"       // continue关键字标记
+	IF             = "IF"             // if关键字标记
+	ELSE           = "ELSE"           // else关键字标记
+	SWITCH         = "SWITCH"         // switch关键字标记
+	CASE           = "CASE"           // case关键字标记
+	DEFAULT        = "DEFAULT"        // default关键字标记
+	DO             = "DO"             // do关键字标记
+	WHILE          = "WHILE"          // while关键字标记
+	FOR            = "FOR"            // for关键字标记
+	AND            = "AND"            // &运算符标记（逻辑与、按位与等情况，这里统一先简单标记为AND，可根据后续需求细化）
+	OR             = "OR"             // |运算符标记（逻辑或、按位或等情况，这里统一先简单标记为OR，可根据后续需求细化）
+	XOR            = "XOR"            // ^运算符标记（逻辑异或、按位异或等情况，这里统一先简单标记为XOR，可根据后续需求细化）
+	NOT            = "NOT"            // ~运算符标记（逻辑非、按位取反等情况，这里统一先简单标记为NOT，可根据后续需求细化）
+	LSHIFT         = "LSHIFT"         // <<运算符标记（左移运算符）
+	RSHIFT         = "RSHIFT"         // >>运算符标记（右移运算符）
+	COLON          = "COLON"          // :运算符标记
+	ANDAND         = "ANDAND"         // &&运算符标记（逻辑与）
+	OROR           = "OROR"           // ||运算符标记（逻辑或）
+	NOTNOT         = "NOTNOT"         //!运算符标记（逻辑非）
+	EQEQ           = "EQEQ"           // ==运算符标记（等于比较）
+	LT             = "LT"             // <运算符标记（小于）
+	GT             = "GT"             // >运算符标记（大于）
+	LTEQ           = "LTEQ"           // <=运算符标记（小于等于）
+	GTEQ           = "GTEQ"           // >=运算符标记（大于等于）
+	COMMENT_SINGLE = "COMMENT_SINGLE" // 单行注释标记
+	COMMENT_MULTI  = "COMMENT_MULTI"  // 多行注释标记
+	CHAR_LIT       = "CHAR_LIT"       // 字符字面量标记，例如 'a'、L'\n'、'\x41'
+
+	MOD      = "MOD"      // %运算符标记（取模）
+	NEQ      = "NEQ"      // !=运算符标记（不等于比较，注意和!本身的NOTNOT区分开）
+	INC      = "INC"      // ++运算符标记（自增）
+	DEC      = "DEC"      // --运算符标记（自减）
+	ARROW    = "ARROW"    // ->运算符标记（通过指针访问成员）
+	QUESTION = "QUESTION" // ?运算符标记（三目表达式的条件部分）
+	ELLIPSIS = "ELLIPSIS" // ...标记（可变参数函数声明里的省略号）
+	DOT      = "DOT"      // .运算符标记（通过结构体/联合体访问成员）
+	LBRACKET = "LBRACKET" // [左方括号
+	RBRACKET = "RBRACKET" // ]右方括号
+
+	PLUSEQ   = "PLUSEQ"   // +=复合赋值
+	MINUSEQ  = "MINUSEQ"  // -=复合赋值
+	STAREQ   = "STAREQ"   // *=复合赋值
+	SLASHEQ  = "SLASHEQ"  // /=复合赋值
+	MODEQ    = "MODEQ"    // %=复合赋值
+	ANDEQ    = "ANDEQ"    // &=复合赋值
+	OREQ     = "OREQ"     // |=复合赋值
+	XOREQ    = "XOREQ"    // ^=复合赋值
+	LSHIFTEQ = "LSHIFTEQ" // <<=复合赋值
+	RSHIFTEQ = "RSHIFTEQ" // >>=复合赋值
+)
+
+// 创建一个映射表，将C语言中的关键字映射到对应的标记类型，以便快速判断一个标识符是否为关键字
+var keywords map[string]TokenType = map[string]TokenType{
+	"int":      INT,
+	"float":    FLOAT,
+	"return":   RETURN,
+	"include":  PREPROC,
+	"void":     VOID,
+	"signed":   SIGNED,
+	"unsigned": UNSIGNED,
+	"short":    SHORT,
+	"long":     LONG,
+	"double":   DOUBLE,
+	"char":     CHAR,
+	"enum":     ENUM,
+	"struct":   STRUCT,
+	"union":    UNION,
+	"typedef":  TYPEDEF,
+	"const":    CONST,
+	"volatile": VOLATILE,
+	"auto":     AUTO,
+	"static":   STATIC,
+	"extern":   EXTERN,
+	"register": REGISTER,
+	"sizeof":   SIZEOF,
+	"goto":     GOTO,
+	"break":    BREAK,
+	"continue": CONTINUE,
+	"if":       IF,
+	"else":     ELSE,
+	"switch":   SWITCH,
+	"case":     CASE,
+	"default":  DEFAULT,
+	"do":       DO,
+	"while":    WHILE,
+	"for":      FOR,
+}
+
+// Keywords 实现 lexcore.Scanner：返回C关键字到标记类型的映射，供上层
+// （比如语言服务器的语义高亮）查询一个词是不是这门语言的保留字
+func (l *Lexer) Keywords() map[string]TokenType {
+	out := make(map[string]TokenType, len(keywords))
+	for k, v := range keywords {
+		out[k] = v
+	}
+	return out
+}
+
+// OperatorTable 实现 lexcore.Scanner：列出C的标点/运算符拼写和对应的
+// 标记类型。直接复用 operatorEntries——这也是运算符trie本身的构建数据，
+// 两边共用一份表，不会出现trie和OperatorTable报告的拼写对不上的情况
+func (l *Lexer) OperatorTable() []lexcore.OpRule {
+	out := make([]lexcore.OpRule, len(operatorEntries))
+	for i, e := range operatorEntries {
+		out[i] = lexcore.OpRule{Text: e.Text, Type: e.Type}
+	}
+	return out
+}
+
+// init 把C前端登记到 lexcore 的全局注册表里，profile名固定为"c"；
+// lex.Lex("c", source) 最终就是走到这里构造出来的 Lexer
+func init() {
+	lexcore.Register("c", func(source, file string) lexcore.Scanner {
+		return NewLexerFromReader(file, strings.NewReader(source))
+	})
+}
+
+// bufSize 是双缓冲区里每一块的大小；readChar/ByteAt 需要更多数据时，
+// 就从reader里再读一块这么大的内容接到已有数据后面
+const bufSize = 4 * 1024
+
+// Lexer 结构
+// 词法分析器不再一次性把整个文件读进内存，而是流式地消费一个io.Reader：
+// buf 保存"目前仍可能被引用到"的已读字节（两个bufSize大小的块交替追加、
+// 旧数据在确认不会再被用到后整体丢弃，效果上等价于经典的双缓冲扫描），
+// bufBase 是 buf[0] 在整个输入流里的绝对偏移。position/readPos 都是绝对
+// 偏移，所以 readChar/ByteAt 的边界检查只在需要再读一块缓冲区时才发生，
+// 热路径上直接数组下标访问即可。
+type Lexer struct {
+	reader  io.Reader
+	file    string
+	buf     []byte
+	bufBase int
+	eof     bool // reader 是否已经耗尽
+
+	position   int // 绝对偏移，指向 l.ch
+	readPos    int // 绝对偏移，指向下一个要读取的字符
+	tokenStart int // 当前正在扫描的token的起始绝对偏移，缓冲区回收不能越过它
+	ch         uint8
+
+	line   int // l.ch 所在的行号（从1开始）
+	column int // l.ch 所在的列号（从1开始）
+}
+
+// NewLexer 用一段已经在内存里的源码字符串构造Lexer，等价于
+// NewLexerFromReader 套一个strings.Reader，主要方便在测试/交互场景下使用
+func NewLexer(input string) *Lexer {
+	return NewLexerFromReader("<string>", strings.NewReader(input))
+}
+
+// NewLexerFromReader 用任意io.Reader构造一个流式Lexer：name会出现在每个
+// Token的File字段里，用于多文件场景（比如预处理展开#include之后）下的错误定位
+func NewLexerFromReader(name string, r io.Reader) *Lexer {
+	l := &Lexer{reader: r, file: name, line: 1, column: 1}
+	l.readChar() // 读取第一个字符，使词法分析器处于初始读取状态
+	return l
+}
+
+// fill 尝试从reader里再读一块（最多bufSize字节）接到buf末尾；
+// 读到io.EOF/io.ErrUnexpectedEOF时说明输入已经耗尽，之后的越界访问都返回哨兵字节0
+func (l *Lexer) fill() {
+	if l.eof {
+		return
+	}
+	chunk := make([]byte, bufSize)
+	n, err := io.ReadFull(l.reader, chunk)
+	if n > 0 {
+		l.buf = append(l.buf, chunk[:n]...)
+	}
+	if err != nil {
+		l.eof = true
+	}
+}
+
+// ByteAt 实现 ByteSource：返回输入流中绝对偏移pos处的字节，
+// 缺数据时透明地从reader里再拉一块，真正到达文件末尾时ok为false
+func (l *Lexer) ByteAt(pos int) (byte, bool) {
+	for pos-l.bufBase >= len(l.buf) && !l.eof {
+		l.fill()
+	}
+	rel := pos - l.bufBase
+	if rel < 0 || rel >= len(l.buf) {
+		return 0, false
+	}
+	return l.buf[rel], true
+}
+
+func (l *Lexer) readChar() {
+	prevCh := l.ch
+
+	for l.readPos-l.bufBase >= len(l.buf) && !l.eof {
+		l.fill()
+	}
+	rel := l.readPos - l.bufBase
+	if rel >= len(l.buf) {
+		l.ch = 0
+	} else {
+		l.ch = l.buf[rel]
+	}
+	l.position = l.readPos
+	l.readPos++
+
+	switch {
+	case prevCh == 0:
+		// 扫描刚开始（或者已经越过文件末尾），不需要调整行列号
+	case prevCh == '\n':
+		l.line++
+		l.column = 1
+	case prevCh == '\r' && l.ch != '\n':
+		// 孤立的 \r（老式 Mac 换行），单独算一次换行
+		l.line++
+		l.column = 1
+	case prevCh == '\r' && l.ch == '\n':
+		// \r\n 序列：换行留给紧随其后的 \n 处理，这里只是跳过 \r 本身
+	default:
+		l.column++
+	}
+
+	l.evictConsumedBuffer()
+}
+
+// evictConsumedBuffer 把buf里早于当前token起点、已经确认用不到的部分
+// 整体丢弃，让内存占用不随输入长度无限增长
+func (l *Lexer) evictConsumedBuffer() {
+	floor := l.tokenStart
+	if l.position < floor {
+		floor = l.position
+	}
+	drop := floor - l.bufBase
+	if drop <= bufSize { // 攒够至少一个缓冲区的陈旧数据再回收，避免频繁的小拷贝
+		return
+	}
+	l.buf = l.buf[drop:]
+	l.bufBase += drop
+}
+
+// 在输入字符串中，遇到空白字符（空格、制表符、换行符、回车符）时，不断读取下一个字符，直到遇到非空白字符
+func (l *Lexer) skipWhitespace() {
+	for l.ch == ' ' || l.ch == '\t' || l.ch == '\n' || l.ch == '\r' {
+		l.readChar()
+	}
+}
+
+// 这是词法分析器的核心函数，用于分析输入字符串并返回下一个词法单元（标记）。
+// 识别分两步：先交给 cTokenEngine——一个在启动时由规则表构建好的DFA，
+// 认识关键字/标识符/数字/字符串/注释；DFA没匹配上时再交给运算符trie，
+// 它专门认标点和运算符，按接下来最多3个字节做最长匹配。两者都没命中
+// 才是真正的非法字符。
+func (l *Lexer) NextToken() Token {
+	l.tokenStart = l.position
+	l.skipWhitespace() // 跳过空白字符，确保从非空白字符开始分析
+	l.tokenStart = l.position
+
+	if l.ch == 0 { // 检查是否到达文件末尾
+		return Token{Type: EOF, File: l.file, StartLine: l.line, StartCol: l.column, EndLine: l.line, EndCol: l.column, Offset: l.position}
+	}
+
+	if token, ok := l.nextByDFA(); ok {
+		return token
+	}
+
+	if token, ok := l.nextByOperatorTrie(); ok {
+		return token
+	}
+
+	// 规则表和运算符trie都没有任何规则能匹配当前字符，视为非法标记
+	startLine, startCol, offset := l.line, l.column, l.position
+	value := fmt.Sprintf("非法字符 '%s' 在第 %d 行，第 %d 列", string(l.ch), startLine, startCol)
+	l.readChar()
+	return Token{
+		Type: ILLEGAL, Value: value, File: l.file,
+		StartLine: startLine, StartCol: startCol,
+		EndLine: l.line, EndCol: l.column,
+		Offset: offset, Length: 1,
+	}
+}
+
+// nextByDFA 从当前位置出发，用 cTokenEngine 做一次最长匹配；
+// 命中时把读取位置推进过整个匹配的文本，并带上完整的位置信息
+func (l *Lexer) nextByDFA() (Token, bool) {
+	startLine, startCol, offset := l.line, l.column, l.position
+	length, typ, ok := cTokenEngine.Match(l, offset)
+	if !ok {
+		return Token{}, false
+	}
+	value := l.readValue(offset, length)
+	for i := 0; i < length; i++ {
+		l.readChar()
+	}
+	tok := Token{
+		Type: typ, Value: value, File: l.file,
+		StartLine: startLine, StartCol: startCol,
+		EndLine: l.line, EndCol: l.column,
+		Offset: offset, Length: length,
+	}
+	attachLiteralValue(&tok)
+	return tok, true
+}
+
+// nextByOperatorTrie 从当前位置出发，用opTrieRoot做一次最长匹配，识别
+// 标点和运算符；命中时把读取位置推进过整个匹配的文本，带上完整的位置信息
+func (l *Lexer) nextByOperatorTrie() (Token, bool) {
+	startLine, startCol, offset := l.line, l.column, l.position
+	length, typ, ok := matchOperator(l, offset)
+	if !ok {
+		return Token{}, false
+	}
+	value := l.readValue(offset, length)
+	for i := 0; i < length; i++ {
+		l.readChar()
+	}
+	return Token{
+		Type: typ, Value: value, File: l.file,
+		StartLine: startLine, StartCol: startCol,
+		EndLine: l.line, EndCol: l.column,
+		Offset: offset, Length: length,
+	}, true
+}
+
+// attachLiteralValue 对数字/字符串/字符字面量做进一步解析，把解码结果
+// 挂到Token上；解析失败（例如转义序列非法）时保留原始lexeme，交由
+// 更上层的诊断去处理，不在这里中断词法分析
+func attachLiteralValue(tok *Token) {
+	var err error
+	switch tok.Type {
+	case INT:
+		tok.IntValue, tok.Suffix, err = parseIntLiteral(tok.Value)
+	case FLOAT:
+		tok.FloatValue, tok.Suffix, err = parseFloatLiteral(tok.Value)
+	case STRING:
+		tok.Prefix, tok.StringValue, err = decodeStringLiteral(tok.Value)
+	case CHAR_LIT:
+		tok.Prefix, tok.IntValue, tok.StringValue, err = decodeCharLiteral(tok.Value)
+	}
+	_ = err // 解析错误不阻塞扫描；Value里的原始文本始终是可靠的兜底
+}
+
+// readValue 按绝对偏移从缓冲区里取出一段已经确认存在的字节，拼成Token的原始文本
+func (l *Lexer) readValue(offset, length int) string {
+	b := make([]byte, length)
+	for i := 0; i < length; i++ {
+		ch, _ := l.ByteAt(offset + i)
+		b[i] = ch
+	}
+	return string(b)
+}
@@ -0,0 +1,178 @@
+package c
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// 本文件负责把INT/FLOAT/STRING/CHAR_LIT的原始词素（lexeme）进一步解析成
+// 真正的数值/解码后的字节内容，挂在Token上，这样语法分析阶段就不需要
+// 再重新理解一遍C的数字进制、后缀和转义规则。
+
+func isHexDigit(ch byte) bool {
+	return (ch >= '0' && ch <= '9') || (ch >= 'a' && ch <= 'f') || (ch >= 'A' && ch <= 'F')
+}
+
+func isIntSuffixByte(ch byte) bool {
+	return ch == 'u' || ch == 'U' || ch == 'l' || ch == 'L'
+}
+
+// parseIntLiteral 识别 0x/0b/0 前缀以及 u/l/ll/ull 等后缀（大小写不敏感），
+// 返回去掉后缀后的数值和原样保留的后缀文本
+func parseIntLiteral(raw string) (value int64, suffix string, err error) {
+	i := len(raw)
+	for i > 0 && isIntSuffixByte(raw[i-1]) {
+		i--
+	}
+	digits, suf := raw[:i], raw[i:]
+
+	var v uint64
+	switch {
+	case len(digits) > 2 && (digits[:2] == "0x" || digits[:2] == "0X"):
+		v, err = strconv.ParseUint(digits[2:], 16, 64)
+	case len(digits) > 2 && (digits[:2] == "0b" || digits[:2] == "0B"):
+		v, err = strconv.ParseUint(digits[2:], 2, 64)
+	case len(digits) > 1 && digits[0] == '0':
+		v, err = strconv.ParseUint(digits[1:], 8, 64)
+	default:
+		v, err = strconv.ParseUint(digits, 10, 64)
+	}
+	return int64(v), suf, err
+}
+
+// parseFloatLiteral 去掉 f/F/l/L 后缀后交给strconv解析，科学计数法和
+// 十六进制浮点数（0x1.8p+1）Go的ParseFloat本身就支持
+func parseFloatLiteral(raw string) (value float64, suffix string, err error) {
+	i := len(raw)
+	for i > 0 {
+		c := raw[i-1]
+		if c == 'f' || c == 'F' || c == 'l' || c == 'L' {
+			i--
+			continue
+		}
+		break
+	}
+	digits, suf := raw[:i], raw[i:]
+	v, err := strconv.ParseFloat(digits, 64)
+	return v, suf, err
+}
+
+// decodeEscapes 把一段C字符串/字符字面量"引号内"的原始文本按转义规则解码，
+// 支持 \n \t \r \a \b \f \v \\ \' \" \? 、八进制 \NNN、\xHH...、\uHHHH、\UHHHHHHHH
+func decodeEscapes(body string) ([]byte, error) {
+	var out []byte
+	i := 0
+	for i < len(body) {
+		ch := body[i]
+		if ch != '\\' {
+			out = append(out, ch)
+			i++
+			continue
+		}
+		i++
+		if i >= len(body) {
+			return nil, fmt.Errorf("转义序列在字面量末尾被截断")
+		}
+		esc := body[i]
+		switch esc {
+		case 'n':
+			out = append(out, '\n')
+			i++
+		case 't':
+			out = append(out, '\t')
+			i++
+		case 'r':
+			out = append(out, '\r')
+			i++
+		case 'a':
+			out = append(out, '\a')
+			i++
+		case 'b':
+			out = append(out, '\b')
+			i++
+		case 'f':
+			out = append(out, '\f')
+			i++
+		case 'v':
+			out = append(out, '\v')
+			i++
+		case '\\', '\'', '"', '?':
+			out = append(out, esc)
+			i++
+		case 'x':
+			i++
+			start := i
+			for i < len(body) && isHexDigit(body[i]) {
+				i++
+			}
+			if i == start {
+				return nil, fmt.Errorf(`\x 转义后面缺少十六进制数字`)
+			}
+			v, err := strconv.ParseUint(body[start:i], 16, 32)
+			if err != nil {
+				return nil, fmt.Errorf(`\x 转义的十六进制数字非法: %w`, err)
+			}
+			out = append(out, byte(v))
+		case 'u', 'U':
+			n := 4
+			if esc == 'U' {
+				n = 8
+			}
+			i++
+			if i+n > len(body) {
+				return nil, fmt.Errorf(`\%c 转义需要%d位十六进制数字`, esc, n)
+			}
+			v, err := strconv.ParseUint(body[i:i+n], 16, 32)
+			if err != nil {
+				return nil, fmt.Errorf(`\%c 转义的十六进制数字非法: %w`, esc, err)
+			}
+			i += n
+			out = append(out, []byte(string(rune(v)))...)
+		default:
+			if esc >= '0' && esc <= '7' {
+				start := i
+				for i < len(body) && i < start+3 && body[i] >= '0' && body[i] <= '7' {
+					i++
+				}
+				v, _ := strconv.ParseUint(body[start:i], 8, 32)
+				out = append(out, byte(v))
+			} else {
+				return nil, fmt.Errorf(`未知的转义序列 \%c`, esc)
+			}
+		}
+	}
+	return out, nil
+}
+
+// decodeStringLiteral 剥掉宽字符前缀（u8/L/u/U）和两端的引号，解码转义序列
+func decodeStringLiteral(raw string) (prefix string, decoded []byte, err error) {
+	i := strings.IndexByte(raw, '"')
+	if i < 0 || raw[len(raw)-1] != '"' || len(raw)-i < 2 {
+		return "", nil, fmt.Errorf("字符串字面量缺少引号")
+	}
+	prefix = raw[:i]
+	decoded, err = decodeEscapes(raw[i+1 : len(raw)-1])
+	return prefix, decoded, err
+}
+
+// decodeCharLiteral 剥掉宽字符前缀和两端的单引号，解码转义序列，并按C里
+// 多字符常量的常见实现（逐字节左移拼接）给出字面量的整数值
+func decodeCharLiteral(raw string) (prefix string, value int64, decoded []byte, err error) {
+	i := strings.IndexByte(raw, '\'')
+	if i < 0 || raw[len(raw)-1] != '\'' || len(raw)-i < 2 {
+		return "", 0, nil, fmt.Errorf("字符字面量缺少引号")
+	}
+	prefix = raw[:i]
+	decoded, err = decodeEscapes(raw[i+1 : len(raw)-1])
+	if err != nil {
+		return prefix, 0, nil, err
+	}
+	if len(decoded) == 0 {
+		return prefix, 0, nil, fmt.Errorf("空的字符字面量")
+	}
+	for _, b := range decoded {
+		value = value<<8 | int64(b)
+	}
+	return prefix, value, decoded, nil
+}
@@ -0,0 +1,117 @@
+package c
+
+import "testing"
+
+// 针对 literals.go 里的几个解码函数做表驱动测试，覆盖进制/后缀/转义的
+// 常见取值和典型边界情况
+func TestParseIntLiteral(t *testing.T) {
+	cases := []struct {
+		raw        string
+		wantValue  int64
+		wantSuffix string
+	}{
+		{"0", 0, ""},
+		{"123", 123, ""},
+		{"0x1A", 0x1A, ""},
+		{"0X1a", 0x1a, ""},
+		{"0b101", 5, ""},
+		{"010", 8, ""},
+		{"123u", 123, "u"},
+		{"123UL", 123, "UL"},
+		{"0x10ULL", 16, "ULL"},
+	}
+	for _, c := range cases {
+		v, suf, err := parseIntLiteral(c.raw)
+		if err != nil {
+			t.Errorf("parseIntLiteral(%q) 返回了意外的错误: %v", c.raw, err)
+			continue
+		}
+		if v != c.wantValue || suf != c.wantSuffix {
+			t.Errorf("parseIntLiteral(%q) = (%d, %q), want (%d, %q)", c.raw, v, suf, c.wantValue, c.wantSuffix)
+		}
+	}
+}
+
+func TestParseFloatLiteral(t *testing.T) {
+	cases := []struct {
+		raw        string
+		wantValue  float64
+		wantSuffix string
+	}{
+		{"1.5", 1.5, ""},
+		{"1.5f", 1.5, "f"},
+		{"1.5F", 1.5, "F"},
+		{".5", 0.5, ""},
+		{"1e10", 1e10, ""},
+		{"1.5L", 1.5, "L"},
+	}
+	for _, c := range cases {
+		v, suf, err := parseFloatLiteral(c.raw)
+		if err != nil {
+			t.Errorf("parseFloatLiteral(%q) 返回了意外的错误: %v", c.raw, err)
+			continue
+		}
+		if v != c.wantValue || suf != c.wantSuffix {
+			t.Errorf("parseFloatLiteral(%q) = (%v, %q), want (%v, %q)", c.raw, v, suf, c.wantValue, c.wantSuffix)
+		}
+	}
+}
+
+func TestDecodeStringLiteral(t *testing.T) {
+	cases := []struct {
+		raw        string
+		wantPrefix string
+		wantBytes  string
+	}{
+		{`"abc"`, "", "abc"},
+		{`"a\nb"`, "", "a\nb"},
+		{`u8"hi"`, "u8", "hi"},
+		{`L"wide"`, "L", "wide"},
+		{`"\x41\x42"`, "", "AB"},
+	}
+	for _, c := range cases {
+		prefix, decoded, err := decodeStringLiteral(c.raw)
+		if err != nil {
+			t.Errorf("decodeStringLiteral(%q) 返回了意外的错误: %v", c.raw, err)
+			continue
+		}
+		if prefix != c.wantPrefix || string(decoded) != c.wantBytes {
+			t.Errorf("decodeStringLiteral(%q) = (%q, %q), want (%q, %q)", c.raw, prefix, decoded, c.wantPrefix, c.wantBytes)
+		}
+	}
+}
+
+func TestDecodeStringLiteralMissingQuote(t *testing.T) {
+	if _, _, err := decodeStringLiteral(`"abc`); err == nil {
+		t.Error("decodeStringLiteral 对缺少闭合引号的输入应当返回错误")
+	}
+}
+
+func TestDecodeCharLiteral(t *testing.T) {
+	cases := []struct {
+		raw        string
+		wantPrefix string
+		wantValue  int64
+	}{
+		{`'a'`, "", int64('a')},
+		{`'\n'`, "", int64('\n')},
+		{`L'a'`, "L", int64('a')},
+		{`'ab'`, "", int64('a')<<8 | int64('b')},
+	}
+	for _, c := range cases {
+		prefix, value, _, err := decodeCharLiteral(c.raw)
+		if err != nil {
+			t.Errorf("decodeCharLiteral(%q) 返回了意外的错误: %v", c.raw, err)
+			continue
+		}
+		if prefix != c.wantPrefix || value != c.wantValue {
+			t.Errorf("decodeCharLiteral(%q) = (%q, %d), want (%q, %d)", c.raw, prefix, value, c.wantPrefix, c.wantValue)
+		}
+	}
+}
+
+func TestDecodeCharLiteralEmpty(t *testing.T) {
+	if _, _, _, err := decodeCharLiteral(`''`); err == nil {
+		t.Error("decodeCharLiteral 对空字符字面量应当返回错误")
+	}
+}
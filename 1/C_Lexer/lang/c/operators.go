@@ -0,0 +1,95 @@
+package c
+
+// opTrieNode 是运算符/标点trie的一个节点：按输入的下一个字节走向某个
+// 子节点，terminal为true时说明"从根到这个节点"这段文本本身就是一个
+// 完整的运算符，tokType是它对应的标记类型
+type opTrieNode struct {
+	children [256]*opTrieNode
+	tokType  TokenType
+	terminal bool
+}
+
+func newOpTrieNode() *opTrieNode {
+	return &opTrieNode{}
+}
+
+func (n *opTrieNode) insert(text string, typ TokenType) {
+	cur := n
+	for i := 0; i < len(text); i++ {
+		b := text[i]
+		if cur.children[b] == nil {
+			cur.children[b] = newOpTrieNode()
+		}
+		cur = cur.children[b]
+	}
+	cur.terminal = true
+	cur.tokType = typ
+}
+
+// operatorEntries 是C标点/运算符的完整拼写表：三字符复合赋值、自增自减、
+// 箭头、三目、省略号、方括号，以及C89的digraph/trigraph替代拼写都在
+// 这里。它既用来构建下面的opTrieRoot，也是 Lexer.OperatorTable 报告给
+// 外部调用者的数据来源——新增一个标点只需要在这里加一行。
+var operatorEntries = []struct {
+	Text string
+	Type TokenType
+}{
+	// 三字符
+	{"<<=", LSHIFTEQ}, {">>=", RSHIFTEQ}, {"...", ELLIPSIS},
+	{"??=", PREPROC}, {"??(", LBRACKET}, {"??)", RBRACKET},
+	{"??<", LBRACE}, {"??>", RBRACE}, {"??!", OR}, {"??'", XOR}, {"??-", NOT},
+
+	// 两字符
+	{"==", EQEQ}, {"!=", NEQ}, {"<=", LTEQ}, {">=", GTEQ},
+	{"&&", ANDAND}, {"||", OROR}, {"<<", LSHIFT}, {">>", RSHIFT},
+	{"+=", PLUSEQ}, {"-=", MINUSEQ}, {"*=", STAREQ}, {"/=", SLASHEQ},
+	{"%=", MODEQ}, {"&=", ANDEQ}, {"|=", OREQ}, {"^=", XOREQ},
+	{"++", INC}, {"--", DEC}, {"->", ARROW},
+	{"<:", LBRACKET}, {":>", RBRACKET}, {"<%", LBRACE}, {"%>", RBRACE}, {"%:", PREPROC},
+
+	// 单字符
+	{"+", PLUS}, {"-", MINUS}, {"*", STAR}, {"/", SLASH}, {"=", EQUAL},
+	{"<", LT}, {">", GT}, {";", SEMICOLON}, {",", COMMA},
+	{"(", LPAREN}, {")", RPAREN}, {"{", LBRACE}, {"}", RBRACE},
+	{"[", LBRACKET}, {"]", RBRACKET},
+	{"&", AND}, {"|", OR}, {"^", XOR}, {"~", NOT}, {":", COLON},
+	{"!", NOTNOT}, {"%", MOD}, {"?", QUESTION}, {".", DOT},
+}
+
+// opTrieRoot 是启动时由operatorEntries构建好的trie，matchOperator从这里
+// 出发做最长匹配
+var opTrieRoot = buildOperatorTrie()
+
+func buildOperatorTrie() *opTrieNode {
+	root := newOpTrieNode()
+	for _, e := range operatorEntries {
+		root.insert(e.Text, e.Type)
+	}
+	return root
+}
+
+// matchOperator 从src的pos位置出发，沿着opTrieRoot按接下来最多3个字节
+// 做最长匹配：每下降一步，只要当前节点是terminal就刷新一次"目前为止
+// 最长的合法匹配"，直到用完输入或者找不到对应的子节点为止
+func matchOperator(src ByteSource, pos int) (length int, typ TokenType, ok bool) {
+	node := opTrieRoot
+	consumed := 0
+	for {
+		if node.terminal {
+			length = consumed
+			typ = node.tokType
+			ok = true
+		}
+		b, has := src.ByteAt(pos + consumed)
+		if !has {
+			break
+		}
+		next := node.children[b]
+		if next == nil {
+			break
+		}
+		node = next
+		consumed++
+	}
+	return length, typ, ok
+}
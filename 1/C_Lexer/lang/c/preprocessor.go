@@ -0,0 +1,883 @@
+package c
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// MacroDef 描述一个宏定义：对象宏形如 `#define NAME value`，
+// 函数宏形如 `#define NAME(a, b) body`，此时 IsFunc 为 true，Params 是形参
+// 列表（`#define NAME() body` 这样的无参函数宏 Params 为空）
+type MacroDef struct {
+	Name     string
+	Params   []string
+	Variadic bool // 最后一个参数是否为 ...（对应展开时的 __VA_ARGS__）
+	Body     string
+	IsFunc   bool
+}
+
+// condFrame 记录一层 #if/#ifdef 条件编译的状态
+type condFrame struct {
+	active       bool // 当前分支（含父层）是否被激活，决定文本是否保留
+	everActive   bool // 本层之前是否已经有分支激活过，#elif/#else 据此判断
+	parentActive bool // 父层是否激活，嵌在未激活的父分支里时任何子分支都不激活
+}
+
+// Preprocessor 实现C11预处理：#include 文件包含、对象/函数宏展开、
+// #if 系列条件编译、#line、#error、#pragma once 等。
+// 它在词法分析之前对源码做一趟整体展开，展开后的文本再交给 Lexer 扫描，
+// 因此 NextToken 永远看不到未展开的预处理指令。
+type Preprocessor struct {
+	searchPaths  []string // #include 搜索路径，按顺序查找
+	macros       map[string]*MacroDef
+	onceGuard    map[string]bool // #pragma once 已处理过的文件（绝对路径）
+	expanding    map[string]bool // 当前正在展开的宏名集合，阻止宏的递归自展开（blue paint）
+	includeChain []string        // 当前包含链，只在 Process 执行期间非空；错误信息和 Token 都不携带它
+	curFile      string
+	curLine      int
+}
+
+// NewPreprocessor 创建一个预处理器，searchPaths 是 #include <...> 的查找目录列表
+func NewPreprocessor(searchPaths []string) *Preprocessor {
+	return &Preprocessor{
+		searchPaths: searchPaths,
+		macros:      make(map[string]*MacroDef),
+		onceGuard:   make(map[string]bool),
+		expanding:   make(map[string]bool),
+	}
+}
+
+// IncludeChain 返回触发当前展开的包含链（从最外层文件到最内层）的副本；
+// Process 返回时会把自己压入的那一层弹出，所以在 Process 之外调用得到的是空切片
+func (p *Preprocessor) IncludeChain() []string {
+	return append([]string(nil), p.includeChain...)
+}
+
+// Process 对名为 file、内容为 src 的源码做完整的预处理展开，
+// 返回展开后的、可以直接交给 Lexer 扫描的文本
+func (p *Preprocessor) Process(file, src string) (string, error) {
+	p.includeChain = append(p.includeChain, file)
+	defer func() { p.includeChain = p.includeChain[:len(p.includeChain)-1] }()
+
+	savedFile, savedLine := p.curFile, p.curLine
+	p.curFile = file
+	defer func() { p.curFile, p.curLine = savedFile, savedLine }()
+
+	var out strings.Builder
+	lines := strings.Split(src, "\n")
+	var condStack []condFrame
+	activeNow := func() bool {
+		for _, f := range condStack {
+			if !f.active {
+				return false
+			}
+		}
+		return true
+	}
+
+	lineBias := 0 // #line 指令造成的行号偏移，之后每一行的诊断行号都要加上它
+	for lineNo, rawLine := range lines {
+		p.curLine = lineNo + 1 + lineBias
+		trimmed := strings.TrimSpace(rawLine)
+		if strings.HasPrefix(trimmed, "#") {
+			directive := strings.TrimSpace(trimmed[1:])
+			word, rest := splitFirstWord(directive)
+			parentActive := activeNow()
+			wroteLine := false
+			switch word {
+			case "ifdef", "ifndef", "if":
+				var cond bool
+				switch word {
+				case "ifdef":
+					_, cond = p.macros[strings.TrimSpace(rest)]
+				case "ifndef":
+					_, cond = p.macros[strings.TrimSpace(rest)]
+					cond = !cond
+				case "if":
+					v, err := p.evalConstExpr(rest)
+					if err != nil {
+						return "", fmt.Errorf("%s:%d: %w", p.curFile, p.curLine, err)
+					}
+					cond = v != 0
+				}
+				condStack = append(condStack, condFrame{active: parentActive && cond, everActive: cond, parentActive: parentActive})
+			case "elif":
+				if len(condStack) == 0 {
+					return "", fmt.Errorf("%s:%d: #elif 没有匹配的 #if", p.curFile, p.curLine)
+				}
+				top := &condStack[len(condStack)-1]
+				if top.everActive || !top.parentActive {
+					top.active = false
+				} else {
+					v, err := p.evalConstExpr(rest)
+					if err != nil {
+						return "", fmt.Errorf("%s:%d: %w", p.curFile, p.curLine, err)
+					}
+					top.active = v != 0
+					top.everActive = top.active
+				}
+			case "else":
+				if len(condStack) == 0 {
+					return "", fmt.Errorf("%s:%d: #else 没有匹配的 #if", p.curFile, p.curLine)
+				}
+				top := &condStack[len(condStack)-1]
+				top.active = top.parentActive && !top.everActive
+				top.everActive = true
+			case "endif":
+				if len(condStack) == 0 {
+					return "", fmt.Errorf("%s:%d: #endif 没有匹配的 #if", p.curFile, p.curLine)
+				}
+				condStack = condStack[:len(condStack)-1]
+			default:
+				if activeNow() {
+					switch word {
+					case "include":
+						included, err := p.handleInclude(file, strings.TrimSpace(rest))
+						if err != nil {
+							return "", fmt.Errorf("%s:%d: %w", p.curFile, p.curLine, err)
+						}
+						out.WriteString(included)
+						out.WriteString("\n")
+						wroteLine = true
+					case "define":
+						if err := p.handleDefine(rest); err != nil {
+							return "", fmt.Errorf("%s:%d: %w", p.curFile, p.curLine, err)
+						}
+					case "undef":
+						delete(p.macros, strings.TrimSpace(rest))
+					case "line":
+						parts := strings.Fields(rest)
+						if len(parts) >= 1 {
+							if n, err := strconv.Atoi(parts[0]); err == nil {
+								lineBias = n - (lineNo + 2) // 紧随其后的物理行编号为 n
+							}
+						}
+						if len(parts) >= 2 {
+							p.curFile = strings.Trim(parts[1], `"`)
+						}
+					case "error":
+						return "", fmt.Errorf("%s:%d: #error %s", p.curFile, p.curLine, rest)
+					case "pragma":
+						if strings.TrimSpace(rest) == "once" {
+							if abs, err := filepath.Abs(file); err == nil {
+								p.onceGuard[abs] = true
+							}
+						}
+						// 其它 #pragma 原样忽略，不是本预处理器关心的范围
+					}
+				}
+			}
+			if !wroteLine {
+				// 指令行本身不产生代码，但仍然要占住这一行，否则后面的
+				// 行号相对于原始文件会整体往前移
+				out.WriteString("\n")
+			}
+			continue
+		}
+
+		if !activeNow() {
+			// 被 #if/#ifdef 排除掉的分支同样只跳过内容、不跳过行号
+			out.WriteString("\n")
+			continue
+		}
+		out.WriteString(p.expandLine(rawLine))
+		out.WriteString("\n")
+	}
+	if len(condStack) != 0 {
+		return "", fmt.Errorf("%s: 存在未闭合的 #if/#ifdef/#ifndef", file)
+	}
+	return out.String(), nil
+}
+
+// splitFirstWord 把 "define FOO 1" 这样的指令体拆成首个单词和剩余部分
+func splitFirstWord(s string) (word, rest string) {
+	s = strings.TrimLeft(s, " \t")
+	i := 0
+	for i < len(s) && (isIdentByte(s[i])) {
+		i++
+	}
+	return s[:i], strings.TrimLeft(s[i:], " \t")
+}
+
+func isIdentByte(ch byte) bool {
+	return ch == '_' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || (ch >= '0' && ch <= '9')
+}
+
+// handleInclude 解析 #include "file" 或 #include <file>，读取并递归预处理其内容，
+// 遇到已经 #pragma once 过的文件直接跳过（返回空内容）
+func (p *Preprocessor) handleInclude(fromFile, rest string) (string, error) {
+	if rest == "" {
+		return "", fmt.Errorf("#include 缺少文件名")
+	}
+	var quoted bool
+	var name string
+	switch rest[0] {
+	case '"':
+		end := strings.IndexByte(rest[1:], '"')
+		if end < 0 {
+			return "", fmt.Errorf("#include 缺少闭合的引号")
+		}
+		name = rest[1 : end+1]
+		quoted = true
+	case '<':
+		end := strings.IndexByte(rest, '>')
+		if end < 0 {
+			return "", fmt.Errorf("#include 缺少闭合的 '>'")
+		}
+		name = rest[1:end]
+	default:
+		return "", fmt.Errorf("#include 后必须是 \"file\" 或 <file>")
+	}
+
+	var candidates []string
+	if quoted {
+		candidates = append(candidates, filepath.Join(filepath.Dir(fromFile), name))
+	}
+	for _, dir := range p.searchPaths {
+		candidates = append(candidates, filepath.Join(dir, name))
+	}
+	candidates = append(candidates, name)
+
+	for _, path := range candidates {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if abs, err := filepath.Abs(path); err == nil && p.onceGuard[abs] {
+			return "", nil
+		}
+		return p.Process(path, string(data))
+	}
+	return "", fmt.Errorf("找不到被包含的文件 %q", name)
+}
+
+// handleDefine 解析 #define 指令体，登记对象宏或函数宏
+func (p *Preprocessor) handleDefine(rest string) error {
+	rest = strings.TrimLeft(rest, " \t")
+	i := 0
+	for i < len(rest) && isIdentByte(rest[i]) {
+		i++
+	}
+	if i == 0 {
+		return fmt.Errorf("#define 缺少宏名")
+	}
+	name := rest[:i]
+	def := &MacroDef{Name: name}
+
+	if i < len(rest) && rest[i] == '(' { // 函数宏：名字后紧跟 ( 中间不能有空格
+		j := strings.IndexByte(rest[i:], ')')
+		if j < 0 {
+			return fmt.Errorf("函数宏 %s 缺少闭合的 ')'", name)
+		}
+		paramList := rest[i+1 : i+j]
+		def.IsFunc = true
+		for _, param := range strings.Split(paramList, ",") {
+			param = strings.TrimSpace(param)
+			if param == "" {
+				continue
+			}
+			if param == "..." {
+				def.Variadic = true
+				param = "__VA_ARGS__"
+			}
+			def.Params = append(def.Params, param)
+		}
+		def.Body = strings.TrimSpace(rest[i+j+1:])
+	} else {
+		def.Body = strings.TrimSpace(rest[i:])
+	}
+	p.macros[name] = def
+	return nil
+}
+
+// expandLine 对一行已经不含指令的源码做宏展开，递归处理直到不动点
+func (p *Preprocessor) expandLine(line string) string {
+	toks := tokenizeForExpansion(line)
+	var out strings.Builder
+	for i := 0; i < len(toks); i++ {
+		tok := toks[i]
+		if !isIdentToken(tok) {
+			out.WriteString(tok)
+			continue
+		}
+		def, ok := p.macros[tok]
+		if !ok || p.expanding[tok] {
+			out.WriteString(tok)
+			continue
+		}
+		if !def.IsFunc {
+			p.expanding[tok] = true
+			out.WriteString(p.expandLine(def.Body))
+			delete(p.expanding, tok)
+			continue
+		}
+		// 函数宏：向后找匹配的 ( ... )，跳过中间的空白 token
+		j := i + 1
+		for j < len(toks) && isBlankToken(toks[j]) {
+			j++
+		}
+		if j >= len(toks) || toks[j] != "(" {
+			out.WriteString(tok) // 没有跟括号，当普通标识符处理
+			continue
+		}
+		args, end := splitMacroArgs(toks, j)
+		expanded := p.expandFuncMacro(def, args)
+		p.expanding[tok] = true
+		out.WriteString(p.expandLine(expanded))
+		delete(p.expanding, tok)
+		i = end
+	}
+	return out.String()
+}
+
+// expandFuncMacro 把函数宏的实参代入宏体，处理 # 字符串化和 ## 记号粘贴
+func (p *Preprocessor) expandFuncMacro(def *MacroDef, args []string) string {
+	argByParam := make(map[string]string, len(def.Params))
+	for idx, param := range def.Params {
+		if idx < len(args) {
+			argByParam[param] = strings.TrimSpace(args[idx])
+		} else {
+			argByParam[param] = ""
+		}
+	}
+	if def.Variadic && len(args) > len(def.Params)-1 {
+		rest := args[len(def.Params)-1:]
+		argByParam["__VA_ARGS__"] = strings.Join(rest, ",")
+	}
+
+	bodyToks := tokenizeForExpansion(def.Body)
+	var out []string
+	for i := 0; i < len(bodyToks); i++ {
+		t := bodyToks[i]
+		switch {
+		case t == "#" && i+1 < len(bodyToks) && isIdentToken(nextNonBlank(bodyToks, i+1)):
+			k, name := nextNonBlankIdx(bodyToks, i+1)
+			if val, ok := argByParam[name]; ok {
+				out = append(out, strconv.Quote(val))
+				i = k
+				continue
+			}
+			out = append(out, t)
+		case isIdentToken(t):
+			if val, ok := argByParam[t]; ok {
+				out = append(out, val)
+			} else {
+				out = append(out, t)
+			}
+		default:
+			out = append(out, t)
+		}
+	}
+	// 处理 ## 记号粘贴：把相邻的 token##token 合并为一个 token
+	joined := make([]string, 0, len(out))
+	// ## 两侧的空白不影响粘贴；合并结果写回右侧的位置，以便继续和后面的 ## 链式粘贴
+	for i := 0; i < len(out); i++ {
+		if !isBlankToken(out[i]) {
+			if k, op := nextNonBlankIdx(out, i+1); op == "##" {
+				if k2, rhs := nextNonBlankIdx(out, k+1); rhs != "" {
+					out[k2] = out[i] + rhs
+					i = k2 - 1
+					continue
+				}
+			}
+		}
+		joined = append(joined, out[i])
+	}
+	return strings.Join(joined, "")
+}
+
+func nextNonBlank(toks []string, from int) string {
+	_, v := nextNonBlankIdx(toks, from)
+	return v
+}
+
+func nextNonBlankIdx(toks []string, from int) (int, string) {
+	for k := from; k < len(toks); k++ {
+		if !isBlankToken(toks[k]) {
+			return k, toks[k]
+		}
+	}
+	return from, ""
+}
+
+// splitMacroArgs 从 toks[openParenIdx] == "(" 开始，按逗号切分实参，
+// 正确处理嵌套括号，返回各实参（已拼接为字符串）以及闭括号的下标
+func splitMacroArgs(toks []string, openParenIdx int) ([]string, int) {
+	depth := 0
+	var args []string
+	var cur strings.Builder
+	i := openParenIdx
+	for ; i < len(toks); i++ {
+		t := toks[i]
+		switch t {
+		case "(":
+			depth++
+			if depth == 1 {
+				continue
+			}
+		case ")":
+			depth--
+			if depth == 0 {
+				args = append(args, cur.String())
+				return args, i
+			}
+		case ",":
+			if depth == 1 {
+				args = append(args, cur.String())
+				cur.Reset()
+				continue
+			}
+		}
+		cur.WriteString(t)
+	}
+	args = append(args, cur.String())
+	return args, len(toks) - 1
+}
+
+// tokenizeForExpansion 把一行代码切成标识符/数字/字符串/单字符标点的序列，
+// 连续空白作为单独的 token 保留，便于原样拼回非宏部分
+func tokenizeForExpansion(line string) []string {
+	var toks []string
+	i := 0
+	for i < len(line) {
+		ch := line[i]
+		switch {
+		case ch == ' ' || ch == '\t':
+			j := i
+			for j < len(line) && (line[j] == ' ' || line[j] == '\t') {
+				j++
+			}
+			toks = append(toks, line[i:j])
+			i = j
+		case isIdentByte(ch) && !(ch >= '0' && ch <= '9'):
+			j := i
+			for j < len(line) && isIdentByte(line[j]) {
+				j++
+			}
+			toks = append(toks, line[i:j])
+			i = j
+		case ch >= '0' && ch <= '9':
+			// 数字连同紧跟的字母数字（0x1F、10UL 之类）作为一个 token
+			j := i
+			for j < len(line) && isIdentByte(line[j]) {
+				j++
+			}
+			toks = append(toks, line[i:j])
+			i = j
+		case ch == '"':
+			j := i + 1
+			for j < len(line) && line[j] != '"' {
+				if line[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j < len(line) {
+				j++
+			}
+			toks = append(toks, line[i:j])
+			i = j
+		case ch == '#' && i+1 < len(line) && line[i+1] == '#':
+			toks = append(toks, "##")
+			i += 2
+		default:
+			toks = append(toks, string(ch))
+			i++
+		}
+	}
+	return toks
+}
+
+func isIdentToken(tok string) bool {
+	if tok == "" || (tok[0] >= '0' && tok[0] <= '9') {
+		return false
+	}
+	for i := 0; i < len(tok); i++ {
+		if !isIdentByte(tok[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isBlankToken(tok string) bool {
+	return strings.TrimSpace(tok) == ""
+}
+
+// evalConstExpr 对 #if/#elif 的常量表达式求值，支持 defined()、整数字面量、
+// 括号以及 ! ~ + - * / % << >> < <= > >= == != & ^ | && || 运算符，
+// 优先级从低到高依次是 || && | ^ & == != 关系运算符 << >> + - * /
+func (p *Preprocessor) evalConstExpr(expr string) (int64, error) {
+	ev := &constExprEval{p: p, toks: tokenizeForExpansion(p.expandLine(preprocessDefinedOperator(expr, p)))}
+	ev.toks = filterBlank(ev.toks)
+	v, err := ev.parseOr()
+	if err != nil {
+		return 0, err
+	}
+	if ev.pos != len(ev.toks) {
+		return 0, fmt.Errorf("#if 表达式存在多余的记号: %q", expr)
+	}
+	return v, nil
+}
+
+func filterBlank(toks []string) []string {
+	out := toks[:0:0]
+	for _, t := range toks {
+		if !isBlankToken(t) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// preprocessDefinedOperator 在宏展开之前先处理 defined(NAME) / defined NAME，
+// 把它替换成 1 或 0，避免 NAME 本身被当作宏展开
+func preprocessDefinedOperator(expr string, p *Preprocessor) string {
+	toks := tokenizeForExpansion(expr)
+	var out strings.Builder
+	for i := 0; i < len(toks); i++ {
+		if toks[i] == "defined" {
+			k, name := nextNonBlankIdx(toks, i+1)
+			if name == "(" {
+				k2, inner := nextNonBlankIdx(toks, k+1)
+				_ = k2
+				if _, ok := p.macros[inner]; ok {
+					out.WriteString("1")
+				} else {
+					out.WriteString("0")
+				}
+				// 跳到对应的右括号
+				j := k + 1
+				depth := 1
+				for j < len(toks) && depth > 0 {
+					if toks[j] == "(" {
+						depth++
+					} else if toks[j] == ")" {
+						depth--
+					}
+					j++
+				}
+				i = j - 1
+				continue
+			}
+			if _, ok := p.macros[name]; ok {
+				out.WriteString("1")
+			} else {
+				out.WriteString("0")
+			}
+			i = k
+			continue
+		}
+		out.WriteString(toks[i])
+	}
+	return out.String()
+}
+
+// constExprEval 是 #if 常量表达式的小型递归下降求值器
+type constExprEval struct {
+	p    *Preprocessor
+	toks []string
+	pos  int
+}
+
+func (e *constExprEval) peek() string {
+	if e.pos >= len(e.toks) {
+		return ""
+	}
+	return e.toks[e.pos]
+}
+
+func (e *constExprEval) next() string {
+	t := e.peek()
+	e.pos++
+	return t
+}
+
+func (e *constExprEval) parseOr() (int64, error) {
+	v, err := e.parseAnd()
+	if err != nil {
+		return 0, err
+	}
+	for e.lookaheadIs("||") {
+		e.pos += 2
+		rhs, err := e.parseAnd()
+		if err != nil {
+			return 0, err
+		}
+		v = boolToInt(v != 0 || rhs != 0)
+	}
+	return v, nil
+}
+
+func (e *constExprEval) lookaheadIs(op string) bool {
+	if e.pos >= len(e.toks) {
+		return false
+	}
+	if e.pos+1 >= len(e.toks) {
+		return op == e.toks[e.pos]
+	}
+	return e.toks[e.pos]+e.toks[e.pos+1] == op
+}
+
+func (e *constExprEval) parseAnd() (int64, error) {
+	v, err := e.parseBitOr()
+	if err != nil {
+		return 0, err
+	}
+	for e.lookaheadIs("&&") {
+		e.pos += 2
+		rhs, err := e.parseBitOr()
+		if err != nil {
+			return 0, err
+		}
+		v = boolToInt(v != 0 && rhs != 0)
+	}
+	return v, nil
+}
+
+// parseBitOr、parseBitXor、parseBitAnd 分别对应按位或/异或/与，三者优先级
+// 介于 && 和 == 之间，且都要小心跳过 ||、&& 这两个由两个相同字符组成的
+// 逻辑运算符——single "|"/"&" 只有在后面不是同一个字符时才算按位运算符
+func (e *constExprEval) parseBitOr() (int64, error) {
+	v, err := e.parseBitXor()
+	if err != nil {
+		return 0, err
+	}
+	for e.peek() == "|" && !e.lookaheadIs("||") {
+		e.next()
+		rhs, err := e.parseBitXor()
+		if err != nil {
+			return 0, err
+		}
+		v |= rhs
+	}
+	return v, nil
+}
+
+func (e *constExprEval) parseBitXor() (int64, error) {
+	v, err := e.parseBitAnd()
+	if err != nil {
+		return 0, err
+	}
+	for e.peek() == "^" {
+		e.next()
+		rhs, err := e.parseBitAnd()
+		if err != nil {
+			return 0, err
+		}
+		v ^= rhs
+	}
+	return v, nil
+}
+
+func (e *constExprEval) parseBitAnd() (int64, error) {
+	v, err := e.parseEquality()
+	if err != nil {
+		return 0, err
+	}
+	for e.peek() == "&" && !e.lookaheadIs("&&") {
+		e.next()
+		rhs, err := e.parseEquality()
+		if err != nil {
+			return 0, err
+		}
+		v &= rhs
+	}
+	return v, nil
+}
+
+func (e *constExprEval) parseEquality() (int64, error) {
+	v, err := e.parseRelational()
+	if err != nil {
+		return 0, err
+	}
+	for e.lookaheadIs("==") || e.lookaheadIs("!=") {
+		op := e.toks[e.pos] + e.toks[e.pos+1]
+		e.pos += 2
+		rhs, err := e.parseRelational()
+		if err != nil {
+			return 0, err
+		}
+		if op == "==" {
+			v = boolToInt(v == rhs)
+		} else {
+			v = boolToInt(v != rhs)
+		}
+	}
+	return v, nil
+}
+
+func (e *constExprEval) parseRelational() (int64, error) {
+	v, err := e.parseShift()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		if e.lookaheadIs("<=") {
+			e.pos += 2
+			rhs, err := e.parseShift()
+			if err != nil {
+				return 0, err
+			}
+			v = boolToInt(v <= rhs)
+		} else if e.lookaheadIs(">=") {
+			e.pos += 2
+			rhs, err := e.parseShift()
+			if err != nil {
+				return 0, err
+			}
+			v = boolToInt(v >= rhs)
+		} else if e.peek() == "<" {
+			e.pos++
+			rhs, err := e.parseShift()
+			if err != nil {
+				return 0, err
+			}
+			v = boolToInt(v < rhs)
+		} else if e.peek() == ">" {
+			e.pos++
+			rhs, err := e.parseShift()
+			if err != nil {
+				return 0, err
+			}
+			v = boolToInt(v > rhs)
+		} else {
+			break
+		}
+	}
+	return v, nil
+}
+
+// parseShift 对应 << >>，优先级高于关系运算符、低于加减法
+func (e *constExprEval) parseShift() (int64, error) {
+	v, err := e.parseAdditive()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		if e.lookaheadIs("<<") {
+			e.pos += 2
+			rhs, err := e.parseAdditive()
+			if err != nil {
+				return 0, err
+			}
+			v <<= uint(rhs)
+		} else if e.lookaheadIs(">>") {
+			e.pos += 2
+			rhs, err := e.parseAdditive()
+			if err != nil {
+				return 0, err
+			}
+			v >>= uint(rhs)
+		} else {
+			break
+		}
+	}
+	return v, nil
+}
+
+func (e *constExprEval) parseAdditive() (int64, error) {
+	v, err := e.parseMul()
+	if err != nil {
+		return 0, err
+	}
+	for e.peek() == "+" || e.peek() == "-" {
+		op := e.next()
+		rhs, err := e.parseMul()
+		if err != nil {
+			return 0, err
+		}
+		if op == "+" {
+			v += rhs
+		} else {
+			v -= rhs
+		}
+	}
+	return v, nil
+}
+
+func (e *constExprEval) parseMul() (int64, error) {
+	v, err := e.parseUnary()
+	if err != nil {
+		return 0, err
+	}
+	for e.peek() == "*" || e.peek() == "/" || e.peek() == "%" {
+		op := e.next()
+		rhs, err := e.parseUnary()
+		if err != nil {
+			return 0, err
+		}
+		switch op {
+		case "*":
+			v *= rhs
+		case "/":
+			if rhs == 0 {
+				return 0, fmt.Errorf("#if 表达式除以零")
+			}
+			v /= rhs
+		case "%":
+			if rhs == 0 {
+				return 0, fmt.Errorf("#if 表达式对零取模")
+			}
+			v %= rhs
+		}
+	}
+	return v, nil
+}
+
+func (e *constExprEval) parseUnary() (int64, error) {
+	switch e.peek() {
+	case "!":
+		e.next()
+		v, err := e.parseUnary()
+		return boolToInt(v == 0), err
+	case "-":
+		e.next()
+		v, err := e.parseUnary()
+		return -v, err
+	case "+":
+		e.next()
+		return e.parseUnary()
+	case "~":
+		e.next()
+		v, err := e.parseUnary()
+		return ^v, err
+	}
+	return e.parsePrimary()
+}
+
+func (e *constExprEval) parsePrimary() (int64, error) {
+	t := e.next()
+	if t == "(" {
+		v, err := e.parseOr()
+		if err != nil {
+			return 0, err
+		}
+		if e.next() != ")" {
+			return 0, fmt.Errorf("#if 表达式缺少闭合的 ')'")
+		}
+		return v, nil
+	}
+	if t == "" {
+		return 0, fmt.Errorf("#if 表达式意外结束")
+	}
+	if t[0] >= '0' && t[0] <= '9' {
+		n, err := strconv.ParseInt(t, 0, 64)
+		if err != nil {
+			return 0, fmt.Errorf("#if 表达式中的非法数字 %q", t)
+		}
+		return n, nil
+	}
+	// 未定义的标识符在常量表达式里按 C 标准规则当作 0
+	return 0, nil
+}
+
+func boolToInt(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
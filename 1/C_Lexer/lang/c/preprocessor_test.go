@@ -0,0 +1,76 @@
+package c
+
+import (
+	"strings"
+	"testing"
+)
+
+// 针对 #if/#elif 常量表达式求值器（evalConstExpr）做表驱动测试，覆盖
+// 逻辑、关系、按位和移位运算符，以及它们之间的优先级
+func TestEvalConstExpr(t *testing.T) {
+	p := NewPreprocessor(nil)
+	p.macros["FLAGS"] = &MacroDef{Name: "FLAGS", Body: "3"}
+
+	cases := []struct {
+		expr string
+		want int64
+	}{
+		{"1", 1},
+		{"1 + 2 * 3", 7},
+		{"(1 + 2) * 3", 9},
+		{"1 == 1 && 2 > 1", 1},
+		{"1 == 2 || 0", 0},
+		{"(FLAGS & 1)", 1},
+		{"(FLAGS & 4)", 0},
+		{"FLAGS | 4", 7},
+		{"FLAGS ^ 1", 2},
+		{"1 << 2", 4},
+		{"(1 << 2) == 4", 1},
+		{"8 >> 2", 2},
+		{"1 << 2 | 1", 5}, // | 的优先级低于 <<
+		{"6 & 3 | 4", 6},  // | 的优先级低于 &：(6&3)|4 = 2|4 = 6
+		{"1 ^ 1 & 1", 0},  // & 的优先级高于 ^：1 ^ (1&1) = 1^1 = 0
+		{"1 << 1 < 4", 1}, // << 的优先级高于关系运算符：(1<<1) < 4
+		{"!0 && ~0 != 0", 1},
+	}
+	for _, c := range cases {
+		v, err := p.evalConstExpr(c.expr)
+		if err != nil {
+			t.Errorf("evalConstExpr(%q) 返回了意外的错误: %v", c.expr, err)
+			continue
+		}
+		if v != c.want {
+			t.Errorf("evalConstExpr(%q) = %d, want %d", c.expr, v, c.want)
+		}
+	}
+}
+
+func TestEvalConstExprErrors(t *testing.T) {
+	p := NewPreprocessor(nil)
+	cases := []string{
+		"(1 + 2", // 缺少闭合括号
+		"1 / 0",  // 除以零
+		"1 2",    // 多余的记号
+	}
+	for _, expr := range cases {
+		if _, err := p.evalConstExpr(expr); err == nil {
+			t.Errorf("evalConstExpr(%q) 应当返回错误", expr)
+		}
+	}
+}
+
+// 被 #if 排除的分支和指令行本身都不应该偷走行号：展开后文本里每一个
+// 原始物理行都要保留一行占位，这样之后的代码在展开文本里仍然落在
+// 和源文件一致的行号上
+func TestProcessPreservesLineNumbers(t *testing.T) {
+	src := "int before = 1;\n#if 0\nint hidden = 2;\nint hidden2 = 3;\n#endif\nint after = 4;\n"
+	p := NewPreprocessor(nil)
+	out, err := p.Process("t.c", src)
+	if err != nil {
+		t.Fatalf("Process 返回了意外的错误: %v", err)
+	}
+	lines := strings.Split(out, "\n")
+	if len(lines) < 6 || !strings.Contains(lines[5], "int after = 4;") {
+		t.Fatalf("int after 没有落在展开文本的第6行: %q", out)
+	}
+}
@@ -0,0 +1,282 @@
+// Package golang 是Go语言的词法分析前端，登记在lexcore里的profile名是
+// "go"。包名不能叫go（那是关键字），所以这里用golang，和目录名lang/go
+// 不一致，但这不影响编译——import路径认目录，包内代码认package子句
+package golang
+
+import "Compilation_principle/1/C_Lexer/lexcore"
+
+// Token 和 TokenType 复用 lexcore 共享的结构，和 lang/c 的做法一样
+type Token = lexcore.Token
+type TokenType = lexcore.TokenType
+
+const (
+	ILLEGAL = "ILLEGAL"
+	EOF     = "EOF"
+	IDENT   = "IDENT"
+	INT     = "INT"
+	STRING  = "STRING"
+
+	FUNC      = "FUNC"
+	PACKAGE   = "PACKAGE"
+	IMPORT    = "IMPORT"
+	VAR       = "VAR"
+	CONST     = "CONST"
+	IF        = "IF"
+	ELSE      = "ELSE"
+	FOR       = "FOR"
+	RANGE     = "RANGE"
+	RETURN    = "RETURN"
+	STRUCT    = "STRUCT"
+	INTERFACE = "INTERFACE"
+	MAP       = "MAP"
+	CHAN      = "CHAN"
+	GO        = "GO"
+	DEFER     = "DEFER"
+	SELECT    = "SELECT"
+	SWITCH    = "SWITCH"
+	CASE      = "CASE"
+	TYPE      = "TYPE"
+	DEFAULT   = "DEFAULT"
+
+	PLUS      = "PLUS"
+	MINUS     = "MINUS"
+	STAR      = "STAR"
+	SLASH     = "SLASH"
+	ASSIGN    = "ASSIGN"
+	DEFINE    = "DEFINE"   // :=
+	ARROW     = "ARROW"    // <-
+	ELLIPSIS  = "ELLIPSIS" // ...
+	EQ        = "EQ"
+	NEQ       = "NEQ"
+	LT        = "LT"
+	GT        = "GT"
+	LTEQ      = "LTEQ"
+	GTEQ      = "GTEQ"
+	ANDAND    = "ANDAND"
+	OROR      = "OROR"
+	NOT       = "NOT"
+	LPAREN    = "LPAREN"
+	RPAREN    = "RPAREN"
+	LBRACE    = "LBRACE"
+	RBRACE    = "RBRACE"
+	LBRACKET  = "LBRACKET"
+	RBRACKET  = "RBRACKET"
+	COMMA     = "COMMA"
+	SEMICOLON = "SEMICOLON"
+	COLON     = "COLON"
+	DOT       = "DOT"
+)
+
+// keywords 是Go语言的保留字表，只收了足够撑起一个可用演示前端的常用子集
+var keywords = map[string]TokenType{
+	"func": FUNC, "package": PACKAGE, "import": IMPORT, "var": VAR, "const": CONST,
+	"if": IF, "else": ELSE, "for": FOR, "range": RANGE, "return": RETURN,
+	"struct": STRUCT, "interface": INTERFACE, "map": MAP, "chan": CHAN,
+	"go": GO, "defer": DEFER, "select": SELECT, "switch": SWITCH, "case": CASE,
+	"type": TYPE, "default": DEFAULT,
+}
+
+// Lexer 是Go源码的简易词法分析器：逐字符手写扫描，满足作为lexcore.Scanner
+// 插件的最低要求——认识Go特有的 := <- ... 等记号和自己的关键字表
+type Lexer struct {
+	input    string
+	position int
+	readPos  int
+	ch       byte
+}
+
+// NewLexer 用一段已经在内存里的源码字符串构造Lexer
+func NewLexer(source string) *Lexer {
+	l := &Lexer{input: source}
+	l.readChar()
+	return l
+}
+
+func (l *Lexer) readChar() {
+	if l.readPos >= len(l.input) {
+		l.ch = 0
+	} else {
+		l.ch = l.input[l.readPos]
+	}
+	l.position = l.readPos
+	l.readPos++
+}
+
+func (l *Lexer) peekChar() byte {
+	if l.readPos >= len(l.input) {
+		return 0
+	}
+	return l.input[l.readPos]
+}
+
+func (l *Lexer) peekAt(offset int) byte {
+	idx := l.position + offset
+	if idx >= len(l.input) {
+		return 0
+	}
+	return l.input[idx]
+}
+
+func (l *Lexer) skipWhitespace() {
+	for l.ch == ' ' || l.ch == '\t' || l.ch == '\n' || l.ch == '\r' {
+		l.readChar()
+	}
+}
+
+func isLetter(ch byte) bool {
+	return ch == '_' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+}
+
+func isDigit(ch byte) bool {
+	return ch >= '0' && ch <= '9'
+}
+
+func (l *Lexer) readIdent() string {
+	start := l.position
+	for isLetter(l.ch) || isDigit(l.ch) {
+		l.readChar()
+	}
+	return l.input[start:l.position]
+}
+
+func (l *Lexer) readNumber() string {
+	start := l.position
+	for isDigit(l.ch) {
+		l.readChar()
+	}
+	return l.input[start:l.position]
+}
+
+func (l *Lexer) readString() string {
+	l.readChar() // 跳过开头的引号
+	start := l.position
+	for l.ch != '"' && l.ch != 0 {
+		if l.ch == '\\' {
+			l.readChar()
+		}
+		l.readChar()
+	}
+	value := l.input[start:l.position]
+	l.readChar() // 跳过结尾的引号
+	return value
+}
+
+func (l *Lexer) two(t TokenType) Token {
+	lit := string(l.ch) + string(l.peekChar())
+	l.readChar()
+	l.readChar()
+	return Token{Type: t, Value: lit}
+}
+
+func (l *Lexer) one(t TokenType) Token {
+	tok := Token{Type: t, Value: string(l.ch)}
+	l.readChar()
+	return tok
+}
+
+// NextToken 实现 lexcore.Scanner：识别下一个Go词法单元
+func (l *Lexer) NextToken() Token {
+	l.skipWhitespace()
+
+	switch {
+	case l.ch == 0:
+		return Token{Type: EOF}
+	case isLetter(l.ch):
+		ident := l.readIdent()
+		if kw, ok := keywords[ident]; ok {
+			return Token{Type: kw, Value: ident}
+		}
+		return Token{Type: IDENT, Value: ident}
+	case isDigit(l.ch):
+		return Token{Type: INT, Value: l.readNumber()}
+	case l.ch == '"':
+		return Token{Type: STRING, Value: l.readString()}
+	case l.ch == ':' && l.peekChar() == '=':
+		return l.two(DEFINE)
+	case l.ch == '<' && l.peekChar() == '-':
+		return l.two(ARROW)
+	case l.ch == '.' && l.peekChar() == '.' && l.peekAt(2) == '.':
+		l.readChar()
+		l.readChar()
+		l.readChar()
+		return Token{Type: ELLIPSIS, Value: "..."}
+	case l.ch == '=' && l.peekChar() == '=':
+		return l.two(EQ)
+	case l.ch == '!' && l.peekChar() == '=':
+		return l.two(NEQ)
+	case l.ch == '<' && l.peekChar() == '=':
+		return l.two(LTEQ)
+	case l.ch == '>' && l.peekChar() == '=':
+		return l.two(GTEQ)
+	case l.ch == '&' && l.peekChar() == '&':
+		return l.two(ANDAND)
+	case l.ch == '|' && l.peekChar() == '|':
+		return l.two(OROR)
+	case l.ch == '+':
+		return l.one(PLUS)
+	case l.ch == '-':
+		return l.one(MINUS)
+	case l.ch == '*':
+		return l.one(STAR)
+	case l.ch == '/':
+		return l.one(SLASH)
+	case l.ch == '=':
+		return l.one(ASSIGN)
+	case l.ch == '<':
+		return l.one(LT)
+	case l.ch == '>':
+		return l.one(GT)
+	case l.ch == '!':
+		return l.one(NOT)
+	case l.ch == '(':
+		return l.one(LPAREN)
+	case l.ch == ')':
+		return l.one(RPAREN)
+	case l.ch == '{':
+		return l.one(LBRACE)
+	case l.ch == '}':
+		return l.one(RBRACE)
+	case l.ch == '[':
+		return l.one(LBRACKET)
+	case l.ch == ']':
+		return l.one(RBRACKET)
+	case l.ch == ',':
+		return l.one(COMMA)
+	case l.ch == ';':
+		return l.one(SEMICOLON)
+	case l.ch == ':':
+		return l.one(COLON)
+	case l.ch == '.':
+		return l.one(DOT)
+	default:
+		return l.one(ILLEGAL)
+	}
+}
+
+// Keywords 实现 lexcore.Scanner
+func (l *Lexer) Keywords() map[string]TokenType {
+	out := make(map[string]TokenType, len(keywords))
+	for k, v := range keywords {
+		out[k] = v
+	}
+	return out
+}
+
+// OperatorTable 实现 lexcore.Scanner
+func (l *Lexer) OperatorTable() []lexcore.OpRule {
+	return []lexcore.OpRule{
+		{Text: ":=", Type: DEFINE}, {Text: "<-", Type: ARROW}, {Text: "...", Type: ELLIPSIS},
+		{Text: "==", Type: EQ}, {Text: "!=", Type: NEQ}, {Text: "<=", Type: LTEQ}, {Text: ">=", Type: GTEQ},
+		{Text: "&&", Type: ANDAND}, {Text: "||", Type: OROR},
+		{Text: "+", Type: PLUS}, {Text: "-", Type: MINUS}, {Text: "*", Type: STAR}, {Text: "/", Type: SLASH},
+		{Text: "=", Type: ASSIGN}, {Text: "<", Type: LT}, {Text: ">", Type: GT}, {Text: "!", Type: NOT},
+		{Text: "(", Type: LPAREN}, {Text: ")", Type: RPAREN}, {Text: "{", Type: LBRACE}, {Text: "}", Type: RBRACE},
+		{Text: "[", Type: LBRACKET}, {Text: "]", Type: RBRACKET},
+		{Text: ",", Type: COMMA}, {Text: ";", Type: SEMICOLON}, {Text: ":", Type: COLON}, {Text: ".", Type: DOT},
+	}
+}
+
+// init 把Go前端登记到 lexcore 的全局注册表里，profile名是"go"
+func init() {
+	lexcore.Register("go", func(source, file string) lexcore.Scanner { return NewLexer(source) })
+}
@@ -0,0 +1,337 @@
+// Package js 是JavaScript的词法分析前端，登记在lexcore里的profile名是"js"
+package js
+
+import "Compilation_principle/1/C_Lexer/lexcore"
+
+// Token 和 TokenType 复用 lexcore 共享的结构，和 lang/c、lang/go 的做法一样
+type Token = lexcore.Token
+type TokenType = lexcore.TokenType
+
+const (
+	ILLEGAL  = "ILLEGAL"
+	EOF      = "EOF"
+	IDENT    = "IDENT"
+	NUMBER   = "NUMBER"
+	STRING   = "STRING"
+	TEMPLATE = "TEMPLATE"
+	REGEX    = "REGEX"
+
+	FUNCTION  = "FUNCTION"
+	VAR       = "VAR"
+	LET       = "LET"
+	CONST     = "CONST"
+	IF        = "IF"
+	ELSE      = "ELSE"
+	FOR       = "FOR"
+	WHILE     = "WHILE"
+	RETURN    = "RETURN"
+	CLASS     = "CLASS"
+	EXTENDS   = "EXTENDS"
+	NEW       = "NEW"
+	TYPEOF    = "TYPEOF"
+	TRUE      = "TRUE"
+	FALSE     = "FALSE"
+	NULL      = "NULL"
+	UNDEFINED = "UNDEFINED"
+	THIS      = "THIS"
+
+	PLUS      = "PLUS"
+	MINUS     = "MINUS"
+	STAR      = "STAR"
+	SLASH     = "SLASH"
+	ASSIGN    = "ASSIGN"
+	ARROW     = "ARROW"  // =>
+	EQ        = "EQ"     // ==
+	EQEQEQ    = "EQEQEQ" // ===
+	NEQ       = "NEQ"    // !=
+	NEQEQ     = "NEQEQ"  // !==
+	LT        = "LT"
+	GT        = "GT"
+	ANDAND    = "ANDAND"
+	OROR      = "OROR"
+	NOT       = "NOT"
+	LPAREN    = "LPAREN"
+	RPAREN    = "RPAREN"
+	LBRACE    = "LBRACE"
+	RBRACE    = "RBRACE"
+	COMMA     = "COMMA"
+	SEMICOLON = "SEMICOLON"
+	DOT       = "DOT"
+)
+
+// keywords 是JS的保留字表，只收了足够撑起一个可用演示前端的常用子集
+var keywords = map[string]TokenType{
+	"function": FUNCTION, "var": VAR, "let": LET, "const": CONST,
+	"if": IF, "else": ELSE, "for": FOR, "while": WHILE, "return": RETURN,
+	"class": CLASS, "extends": EXTENDS, "new": NEW, "typeof": TYPEOF,
+	"true": TRUE, "false": FALSE, "null": NULL, "undefined": UNDEFINED, "this": THIS,
+}
+
+// Lexer 是JS源码的简易词法分析器。和C/Go不同，JS里'/'是不是正则表达式
+// 的开始要看上一个有意义的token——跟在标识符、数字、字符串/模板/正则
+// 字面量、')'、'}'以及this/true/false/null/undefined后面时是除号，否则
+// 当成正则字面量的起点；lastSignificant就是用来记这个的。本前端不识别
+// '['和']'（它们产出ILLEGAL），所以']'后面的'/'也会被当成正则的起点
+type Lexer struct {
+	input           string
+	position        int
+	readPos         int
+	ch              byte
+	lastSignificant TokenType
+}
+
+// NewLexer 用一段已经在内存里的源码字符串构造Lexer
+func NewLexer(source string) *Lexer {
+	l := &Lexer{input: source}
+	l.readChar()
+	return l
+}
+
+func (l *Lexer) readChar() {
+	if l.readPos >= len(l.input) {
+		l.ch = 0
+	} else {
+		l.ch = l.input[l.readPos]
+	}
+	l.position = l.readPos
+	l.readPos++
+}
+
+func (l *Lexer) peekChar() byte {
+	if l.readPos >= len(l.input) {
+		return 0
+	}
+	return l.input[l.readPos]
+}
+
+func (l *Lexer) peekAt(offset int) byte {
+	idx := l.position + offset
+	if idx >= len(l.input) {
+		return 0
+	}
+	return l.input[idx]
+}
+
+func (l *Lexer) skipWhitespace() {
+	for l.ch == ' ' || l.ch == '\t' || l.ch == '\n' || l.ch == '\r' {
+		l.readChar()
+	}
+}
+
+func isLetter(ch byte) bool {
+	return ch == '_' || ch == '$' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+}
+
+func isDigit(ch byte) bool {
+	return ch >= '0' && ch <= '9'
+}
+
+func (l *Lexer) readIdent() string {
+	start := l.position
+	for isLetter(l.ch) || isDigit(l.ch) {
+		l.readChar()
+	}
+	return l.input[start:l.position]
+}
+
+func (l *Lexer) readNumber() string {
+	start := l.position
+	for isDigit(l.ch) || l.ch == '.' {
+		l.readChar()
+	}
+	return l.input[start:l.position]
+}
+
+func (l *Lexer) readQuoted(quote byte) string {
+	l.readChar()
+	start := l.position
+	for l.ch != quote && l.ch != 0 {
+		if l.ch == '\\' {
+			l.readChar()
+		}
+		l.readChar()
+	}
+	value := l.input[start:l.position]
+	l.readChar()
+	return value
+}
+
+// readTemplate 把反引号模板字面量整体当成一个token；内部的${...}占位符
+// 不递归分词，原样保留在Value里——足够让工具识别"这是一段模板字符串"，
+// 真要对占位符里的表达式做语法分析，交给更上层专门处理
+func (l *Lexer) readTemplate() string {
+	l.readChar()
+	start := l.position
+	depth := 0
+	for l.ch != 0 {
+		if l.ch == '\\' {
+			l.readChar()
+		} else if l.ch == '`' && depth == 0 {
+			break
+		} else if l.ch == '$' && l.peekChar() == '{' {
+			depth++
+		} else if l.ch == '}' && depth > 0 {
+			depth--
+		}
+		l.readChar()
+	}
+	value := l.input[start:l.position]
+	l.readChar()
+	return value
+}
+
+func (l *Lexer) readRegex() string {
+	l.readChar() // 跳过开头的 /
+	start := l.position
+	inClass := false
+	for l.ch != 0 && (l.ch != '/' || inClass) {
+		if l.ch == '\\' {
+			l.readChar()
+		} else if l.ch == '[' {
+			inClass = true
+		} else if l.ch == ']' {
+			inClass = false
+		}
+		l.readChar()
+	}
+	body := l.input[start:l.position]
+	l.readChar() // 跳过结尾的 /
+	flagsStart := l.position
+	for isLetter(l.ch) {
+		l.readChar()
+	}
+	return "/" + body + "/" + l.input[flagsStart:l.position]
+}
+
+// regexAllowed 判断当前'/'这个位置上，上一个有意义的token是否允许
+// 后面紧跟一个正则字面量（而不是把'/'当成除号）
+func (l *Lexer) regexAllowed() bool {
+	switch l.lastSignificant {
+	case IDENT, NUMBER, STRING, TEMPLATE, REGEX, RPAREN, RBRACE, THIS, TRUE, FALSE, NULL, UNDEFINED:
+		return false
+	default:
+		return true
+	}
+}
+
+func (l *Lexer) two(t TokenType) Token {
+	lit := string(l.ch) + string(l.peekChar())
+	l.readChar()
+	l.readChar()
+	return l.emit(t, lit)
+}
+
+func (l *Lexer) three(t TokenType) Token {
+	lit := l.input[l.position : l.position+3]
+	l.readChar()
+	l.readChar()
+	l.readChar()
+	return l.emit(t, lit)
+}
+
+func (l *Lexer) one(t TokenType) Token {
+	lit := string(l.ch)
+	l.readChar()
+	return l.emit(t, lit)
+}
+
+func (l *Lexer) emit(t TokenType, value string) Token {
+	l.lastSignificant = t
+	return Token{Type: t, Value: value}
+}
+
+// NextToken 实现 lexcore.Scanner：识别下一个JS词法单元
+func (l *Lexer) NextToken() Token {
+	l.skipWhitespace()
+
+	switch {
+	case l.ch == 0:
+		return Token{Type: EOF}
+	case isLetter(l.ch):
+		ident := l.readIdent()
+		if kw, ok := keywords[ident]; ok {
+			return l.emit(kw, ident)
+		}
+		return l.emit(IDENT, ident)
+	case isDigit(l.ch):
+		return l.emit(NUMBER, l.readNumber())
+	case l.ch == '"' || l.ch == '\'':
+		return l.emit(STRING, l.readQuoted(l.ch))
+	case l.ch == '`':
+		return l.emit(TEMPLATE, l.readTemplate())
+	case l.ch == '/' && l.regexAllowed():
+		return l.emit(REGEX, l.readRegex())
+	case l.ch == '=' && l.peekChar() == '=' && l.peekAt(2) == '=':
+		return l.three(EQEQEQ)
+	case l.ch == '!' && l.peekChar() == '=' && l.peekAt(2) == '=':
+		return l.three(NEQEQ)
+	case l.ch == '=' && l.peekChar() == '>':
+		return l.two(ARROW)
+	case l.ch == '=' && l.peekChar() == '=':
+		return l.two(EQ)
+	case l.ch == '!' && l.peekChar() == '=':
+		return l.two(NEQ)
+	case l.ch == '&' && l.peekChar() == '&':
+		return l.two(ANDAND)
+	case l.ch == '|' && l.peekChar() == '|':
+		return l.two(OROR)
+	case l.ch == '+':
+		return l.one(PLUS)
+	case l.ch == '-':
+		return l.one(MINUS)
+	case l.ch == '*':
+		return l.one(STAR)
+	case l.ch == '/':
+		return l.one(SLASH)
+	case l.ch == '=':
+		return l.one(ASSIGN)
+	case l.ch == '<':
+		return l.one(LT)
+	case l.ch == '>':
+		return l.one(GT)
+	case l.ch == '!':
+		return l.one(NOT)
+	case l.ch == '(':
+		return l.one(LPAREN)
+	case l.ch == ')':
+		return l.one(RPAREN)
+	case l.ch == '{':
+		return l.one(LBRACE)
+	case l.ch == '}':
+		return l.one(RBRACE)
+	case l.ch == ',':
+		return l.one(COMMA)
+	case l.ch == ';':
+		return l.one(SEMICOLON)
+	case l.ch == '.':
+		return l.one(DOT)
+	default:
+		return l.one(ILLEGAL)
+	}
+}
+
+// Keywords 实现 lexcore.Scanner
+func (l *Lexer) Keywords() map[string]TokenType {
+	out := make(map[string]TokenType, len(keywords))
+	for k, v := range keywords {
+		out[k] = v
+	}
+	return out
+}
+
+// OperatorTable 实现 lexcore.Scanner
+func (l *Lexer) OperatorTable() []lexcore.OpRule {
+	return []lexcore.OpRule{
+		{Text: "===", Type: EQEQEQ}, {Text: "!==", Type: NEQEQ}, {Text: "=>", Type: ARROW},
+		{Text: "==", Type: EQ}, {Text: "!=", Type: NEQ}, {Text: "&&", Type: ANDAND}, {Text: "||", Type: OROR},
+		{Text: "+", Type: PLUS}, {Text: "-", Type: MINUS}, {Text: "*", Type: STAR}, {Text: "/", Type: SLASH},
+		{Text: "=", Type: ASSIGN}, {Text: "<", Type: LT}, {Text: ">", Type: GT}, {Text: "!", Type: NOT},
+		{Text: "(", Type: LPAREN}, {Text: ")", Type: RPAREN}, {Text: "{", Type: LBRACE}, {Text: "}", Type: RBRACE},
+		{Text: ",", Type: COMMA}, {Text: ";", Type: SEMICOLON}, {Text: ".", Type: DOT},
+	}
+}
+
+// init 把JS前端登记到 lexcore 的全局注册表里，profile名是"js"
+func init() {
+	lexcore.Register("js", func(source, file string) lexcore.Scanner { return NewLexer(source) })
+}
@@ -0,0 +1,34 @@
+// Package lex 提供一个统一的入口：不管源码是哪种语言，调用方只要报出
+// profile名字（"c"/"go"/"js"……），就能拿到展开后的完整Token序列，
+// 不需要知道具体是lang下的哪个包在干活
+package lex
+
+import "Compilation_principle/1/C_Lexer/lexcore"
+
+// Lex 按profile选定语言前端，扫描source，一次性返回全部Token（包括
+// 最后的EOF）。小工具/测试场景下比直接暴露Scanner迭代器更方便调用；
+// 不关心Token.File具体是什么的调用方可以用这个省事的版本
+func Lex(profile, source string) ([]lexcore.Token, error) {
+	return LexFile(profile, "<string>", source)
+}
+
+// LexFile 和 Lex 一样，额外报出source的来源文件名；"c"前端产出的每个
+// Token.File都会是这个值，"go"/"js"前端忽略file，Token.File留空——
+// 预处理之后的展开文本应该用原始文件名调用这个版本，
+// 这样下游（JSON输出、LSP诊断……）才能把token定位回真实文件
+func LexFile(profile, file, source string) ([]lexcore.Token, error) {
+	scanner, err := lexcore.New(profile, source, file)
+	if err != nil {
+		return nil, err
+	}
+
+	var tokens []lexcore.Token
+	for {
+		tok := scanner.NextToken()
+		tokens = append(tokens, tok)
+		if tok.Type == "EOF" {
+			break
+		}
+	}
+	return tokens, nil
+}
@@ -0,0 +1,32 @@
+package lex
+
+import (
+	"testing"
+
+	_ "Compilation_principle/1/C_Lexer/lang/c" // 触发init()把"c"这个profile登记进lexcore
+)
+
+// Lex 应该保留原先"不关心文件名"的省事行为，默认报出"<string>"
+func TestLexDefaultsFileToStringLiteral(t *testing.T) {
+	tokens, err := Lex("c", "int x;")
+	if err != nil {
+		t.Fatalf("Lex 返回了意外的错误: %v", err)
+	}
+	if len(tokens) == 0 || tokens[0].File != "<string>" {
+		t.Fatalf("Lex 产出的Token.File = %q, want %q", tokens[0].File, "<string>")
+	}
+}
+
+// LexFile 是真正报出来源文件名的入口：每个token的File字段都应该落在
+// 调用者传进来的文件名上，而不是硬编码的占位符
+func TestLexFileReportsCallerFileName(t *testing.T) {
+	tokens, err := LexFile("c", "main.c", "int x;")
+	if err != nil {
+		t.Fatalf("LexFile 返回了意外的错误: %v", err)
+	}
+	for _, tok := range tokens {
+		if tok.File != "main.c" {
+			t.Fatalf("token %+v 的File没有落在调用方传入的文件名上", tok)
+		}
+	}
+}
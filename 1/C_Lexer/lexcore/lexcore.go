@@ -0,0 +1,72 @@
+// Package lexcore 定义所有语言前端共享的最小契约：Token的结构、
+// TokenType这个类型本身、以及一个语言前端要实现的Scanner接口。
+// 具体某门语言认识哪些关键字、哪些运算符，都留给 lang/c、lang/go、
+// lang/js 这些实现包自己决定，这里只负责把它们粘合起来。
+package lexcore
+
+import "fmt"
+
+// TokenType 标记类型：具体取值由每个语言前端自己定义成字符串常量，
+// lexcore本身不关心某个值到底代表什么语法成分
+type TokenType string
+
+// Token 是所有语言前端共享的标记结构，携带足够的位置信息和（对字面量）
+// 解析后的值，下游的语法分析器、JSON输出、LSP等都只需要认识这一种结构，
+// 不需要关心Token是由哪个语言前端产生的
+type Token struct {
+	Type      TokenType
+	Value     string
+	File      string
+	StartLine int
+	StartCol  int
+	EndLine   int
+	EndCol    int
+	Offset    int
+	Length    int
+
+	// 下面几个字段只在对应的Type上才有意义，具体解读交给产生这个Token的
+	// 语言前端自己的文档
+	Prefix      string
+	Suffix      string
+	IntValue    int64
+	FloatValue  float64
+	StringValue []byte
+}
+
+// OpRule 描述一个运算符/标点的拼写和它对应的标记类型，供 Scanner.OperatorTable
+// 使用，方便外部工具（比如语法高亮）在不理解某门语言内部细节的情况下
+// 列出它支持哪些运算符
+type OpRule struct {
+	Text string
+	Type TokenType
+}
+
+// Scanner 是可插拔语言前端要实现的最小接口：不断产生下一个Token，
+// 并能报告自己的关键字表和运算符表。C/Go/JS各自在 lang 下实现一份，
+// Lex()/New() 再按名字选出具体用哪个
+type Scanner interface {
+	NextToken() Token
+	Keywords() map[string]TokenType
+	OperatorTable() []OpRule
+}
+
+// Factory 从一段源码字符串和它的来源文件名构造出该语言的一个Scanner实例；
+// file会被产出的Token.File字段报告出来，不需要这个信息的前端可以直接忽略
+type Factory func(source, file string) Scanner
+
+var registry = map[string]Factory{}
+
+// Register 登记一个语言前端，各 lang/* 包在自己的 init() 里调用；
+// 新增一种语言只需要新写一个实现了Scanner的包，不需要改这里或者main.go
+func Register(profile string, factory Factory) {
+	registry[profile] = factory
+}
+
+// New 按profile名字构造一个Scanner；profile未登记过时返回错误
+func New(profile, source, file string) (Scanner, error) {
+	factory, ok := registry[profile]
+	if !ok {
+		return nil, fmt.Errorf("lexcore: 未知的语言profile %q", profile)
+	}
+	return factory(source, file), nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"Compilation_principle/1/C_Lexer/format"
+	"Compilation_principle/1/C_Lexer/lang/c"
+	"Compilation_principle/1/C_Lexer/lex"
+)
+
+func main() {
+	formatFlag := flag.String("format", "text", "输出格式：text、json 或 jsonl")
+	flag.Parse()
+
+	furl := `test.c` //输入文件的路径
+	data, err := os.ReadFile(furl)
+	if err != nil {
+		fmt.Println("未找到对应文件" + furl)
+		return
+	}
+
+	// 先跑一趟预处理：展开 #include/#define，求值 #if 系列条件编译，
+	// 这样词法分析看到的永远是已经展开完毕的纯C源码，不需要再认识任何指令
+	pp := c.NewPreprocessor([]string{"."})
+	expanded, err := pp.Process(furl, string(data))
+	if err != nil {
+		fmt.Println("预处理失败：", err)
+		return
+	}
+
+	// 统一入口：profile选"c"就交给lang/c扫描，换成"go"/"js"就是另一个前端，
+	// main.go本身不需要认识任何具体语言的词法细节
+	tokens, err := lex.LexFile("c", furl, expanded)
+	if err != nil {
+		fmt.Println("词法分析失败：", err)
+		return
+	}
+	// EOF本身对下游工具（不管是文本还是json/jsonl输出）都没有意义，统一砍掉
+	if len(tokens) > 0 && tokens[len(tokens)-1].Type == "EOF" {
+		tokens = tokens[:len(tokens)-1]
+	}
+
+	switch *formatFlag {
+	case "json":
+		if err := format.WriteJSON(os.Stdout, tokens); err != nil {
+			fmt.Println("JSON编码失败：", err)
+		}
+	case "jsonl":
+		if err := format.WriteJSONL(os.Stdout, tokens); err != nil {
+			fmt.Println("JSONL编码失败：", err)
+		}
+	default:
+		format.WriteText(os.Stdout, tokens)
+	}
+}